@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/devopsext/tools/common"
+	"github.com/devopsext/tools/vendors"
+	"github.com/devopsext/utils"
+	"github.com/spf13/cobra"
+)
+
+// Confluence uses the same auth as Jira, JIRA_* variables are used if CONFLUENCE_* are not set
+var confluenceOptions = vendors.JiraOptions{
+	URL:         envGet("CONFLUENCE_URL", "").(string),
+	Timeout:     envGet("CONFLUENCE_TIMEOUT", 30).(int),
+	Insecure:    envGet("CONFLUENCE_INSECURE", false).(bool),
+	User:        envGet("CONFLUENCE_USER", envGet("JIRA_USER", "")).(string),
+	Password:    envGet("CONFLUENCE_PASSWORD", envGet("JIRA_PASSWORD", "")).(string),
+	AccessToken: envGet("CONFLUENCE_ACCESS_TOKEN", envGet("JIRA_ACCESS_TOKEN", "")).(string),
+	Email:       envGet("CONFLUENCE_EMAIL", envGet("JIRA_EMAIL", "")).(string),
+	APIToken:    envGet("CONFLUENCE_API_TOKEN", envGet("JIRA_API_TOKEN", "")).(string),
+}
+
+var confluencePageOptions = vendors.ConfluencePageOptions{
+	ID:             envGet("CONFLUENCE_PAGE_ID", "").(string),
+	SpaceKey:       envGet("CONFLUENCE_PAGE_SPACE", "").(string),
+	Title:          envGet("CONFLUENCE_PAGE_TITLE", "").(string),
+	ParentID:       envGet("CONFLUENCE_PAGE_PARENT_ID", "").(string),
+	Body:           envGet("CONFLUENCE_PAGE_BODY", "").(string),
+	Representation: envGet("CONFLUENCE_PAGE_REPRESENTATION", "storage").(string),
+	VersionMessage: envGet("CONFLUENCE_PAGE_VERSION_MESSAGE", "").(string),
+	Labels:         common.RemoveEmptyStrings(strings.Split(envGet("CONFLUENCE_PAGE_LABELS", "").(string), ",")),
+}
+
+var confluenceAddAttachmentOptions = vendors.ConfluenceAddAttachmentOptions{
+	File:    envGet("CONFLUENCE_ATTACHMENT_FILE", "").(string),
+	Name:    envGet("CONFLUENCE_ATTACHMENT_NAME", "").(string),
+	Comment: envGet("CONFLUENCE_ATTACHMENT_COMMENT", "").(string),
+}
+
+var confluenceLabelsOptions = vendors.ConfluenceLabelsOptions{
+	Add:    common.RemoveEmptyStrings(strings.Split(envGet("CONFLUENCE_LABELS_ADD", "").(string), ",")),
+	Remove: common.RemoveEmptyStrings(strings.Split(envGet("CONFLUENCE_LABELS_REMOVE", "").(string), ",")),
+}
+
+var confluenceOutput = common.OutputOptions{
+	Output: envGet("CONFLUENCE_OUTPUT", "").(string),
+	Query:  envGet("CONFLUENCE_OUTPUT_QUERY", "").(string),
+}
+
+func confluenceNew(stdout *common.Stdout) *vendors.Confluence {
+
+	common.Debug("Confluence", confluenceOptions, stdout)
+	common.Debug("Confluence", confluenceOutput, stdout)
+
+	return vendors.NewConfluence(confluenceOptions)
+}
+
+func confluencePageBody(stdout *common.Stdout) {
+
+	bodyBytes, err := utils.Content(confluencePageOptions.Body)
+	if err != nil {
+		stdout.Panic(err)
+	}
+	confluencePageOptions.Body = string(bodyBytes)
+}
+
+func NewConfluenceCommand() *cobra.Command {
+
+	confluenceCmd := &cobra.Command{
+		Use:   "confluence",
+		Short: "Confluence tools",
+	}
+	flags := confluenceCmd.PersistentFlags()
+	flags.StringVar(&confluenceOptions.URL, "confluence-url", confluenceOptions.URL, "Confluence URL (with /wiki for Cloud)")
+	flags.IntVar(&confluenceOptions.Timeout, "confluence-timeout", confluenceOptions.Timeout, "Confluence timeout")
+	flags.BoolVar(&confluenceOptions.Insecure, "confluence-insecure", confluenceOptions.Insecure, "Confluence insecure")
+	flags.StringVar(&confluenceOptions.User, "confluence-user", confluenceOptions.User, "Confluence user")
+	flags.StringVar(&confluenceOptions.Password, "confluence-password", confluenceOptions.Password, "Confluence password")
+	flags.StringVar(&confluenceOptions.AccessToken, "confluence-access-token", confluenceOptions.AccessToken, "Confluence Personal Access Token")
+	flags.StringVar(&confluenceOptions.Email, "confluence-email", confluenceOptions.Email, "Confluence Cloud user email")
+	flags.StringVar(&confluenceOptions.APIToken, "confluence-api-token", confluenceOptions.APIToken, "Confluence Cloud API token")
+	flags.StringVar(&confluenceOutput.Output, "confluence-output", confluenceOutput.Output, "Confluence output")
+	flags.StringVar(&confluenceOutput.Query, "confluence-output-query", confluenceOutput.Query, "Confluence output query")
+
+	pageCmd := &cobra.Command{
+		Use:   "page",
+		Short: "Page methods",
+	}
+	flags = pageCmd.PersistentFlags()
+	flags.StringVar(&confluencePageOptions.ID, "confluence-page-id", confluencePageOptions.ID, "Confluence page ID")
+	flags.StringVar(&confluencePageOptions.SpaceKey, "confluence-page-space", confluencePageOptions.SpaceKey, "Confluence page space key")
+	flags.StringVar(&confluencePageOptions.Title, "confluence-page-title", confluencePageOptions.Title, "Confluence page title")
+	confluenceCmd.AddCommand(pageCmd)
+
+	pageBodyFlags := func(cmd *cobra.Command) {
+		flags := cmd.PersistentFlags()
+		flags.StringVar(&confluencePageOptions.Body, "confluence-page-body", confluencePageOptions.Body, "Confluence page body content or path")
+		flags.StringVar(&confluencePageOptions.Representation, "confluence-page-representation", confluencePageOptions.Representation, "Confluence page body representation: storage, wiki")
+		flags.StringVar(&confluencePageOptions.ParentID, "confluence-page-parent-id", confluencePageOptions.ParentID, "Confluence page parent ID")
+		flags.StringSliceVar(&confluencePageOptions.Labels, "confluence-page-labels", confluencePageOptions.Labels, "Confluence page labels to add")
+	}
+
+	// tools confluence page create --confluence-params --confluence-page-space OPS --confluence-page-title "Weekly report" --confluence-page-body report.html
+	pageCreateCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create page",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Confluence page creating...")
+			common.Debug("Confluence", confluencePageOptions, stdout)
+
+			confluencePageBody(stdout)
+
+			bytes, err := confluenceNew(stdout).CreatePage(confluencePageOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(confluenceOutput, "Confluence", []interface{}{confluenceOptions, confluencePageOptions}, bytes, stdout)
+		},
+	}
+	pageBodyFlags(pageCreateCmd)
+	pageCmd.AddCommand(pageCreateCmd)
+
+	// tools confluence page update --confluence-params --confluence-page-space OPS --confluence-page-title "Weekly report" --confluence-page-body report.html
+	pageUpdateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update page",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Confluence page updating...")
+			common.Debug("Confluence", confluencePageOptions, stdout)
+
+			confluencePageBody(stdout)
+
+			bytes, err := confluenceNew(stdout).UpdatePage(confluencePageOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(confluenceOutput, "Confluence", []interface{}{confluenceOptions, confluencePageOptions}, bytes, stdout)
+		},
+	}
+	pageBodyFlags(pageUpdateCmd)
+	flags = pageUpdateCmd.PersistentFlags()
+	flags.StringVar(&confluencePageOptions.VersionMessage, "confluence-page-version-message", confluencePageOptions.VersionMessage, "Confluence page version message")
+	pageCmd.AddCommand(pageUpdateCmd)
+
+	// tools confluence page get --confluence-params --confluence-page-space OPS --confluence-page-title "Weekly report"
+	pageGetCmd := &cobra.Command{
+		Use:   "get",
+		Short: "Get page",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Confluence page getting...")
+			common.Debug("Confluence", confluencePageOptions, stdout)
+
+			bytes, err := confluenceNew(stdout).GetPage(confluencePageOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(confluenceOutput, "Confluence", []interface{}{confluenceOptions, confluencePageOptions}, bytes, stdout)
+		},
+	}
+	pageCmd.AddCommand(pageGetCmd)
+
+	// tools confluence page add-attachment --confluence-params --confluence-page-id 123 --confluence-attachment-file panel.png
+	pageAddAttachmentCmd := &cobra.Command{
+		Use:   "add-attachment",
+		Short: "Add page attachment",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Confluence page adding attachment...")
+			common.Debug("Confluence", confluencePageOptions, stdout)
+			common.Debug("Confluence", confluenceAddAttachmentOptions, stdout)
+
+			if utils.IsEmpty(confluenceAddAttachmentOptions.Name) && utils.FileExists(confluenceAddAttachmentOptions.File) {
+				confluenceAddAttachmentOptions.Name = filepath.Base(confluenceAddAttachmentOptions.File)
+			}
+
+			fileBytes, err := utils.Content(confluenceAddAttachmentOptions.File)
+			if err != nil {
+				stdout.Panic(err)
+			}
+			confluenceAddAttachmentOptions.File = string(fileBytes)
+
+			bytes, err := confluenceNew(stdout).AddPageAttachment(confluencePageOptions, confluenceAddAttachmentOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(confluenceOutput, "Confluence", []interface{}{confluenceOptions, confluencePageOptions, confluenceAddAttachmentOptions}, bytes, stdout)
+		},
+	}
+	flags = pageAddAttachmentCmd.PersistentFlags()
+	flags.StringVar(&confluenceAddAttachmentOptions.File, "confluence-attachment-file", confluenceAddAttachmentOptions.File, "Confluence attachment file")
+	flags.StringVar(&confluenceAddAttachmentOptions.Name, "confluence-attachment-name", confluenceAddAttachmentOptions.Name, "Confluence attachment name")
+	flags.StringVar(&confluenceAddAttachmentOptions.Comment, "confluence-attachment-comment", confluenceAddAttachmentOptions.Comment, "Confluence attachment comment")
+	pageCmd.AddCommand(pageAddAttachmentCmd)
+
+	// tools confluence page labels --confluence-params --confluence-page-id 123 --confluence-labels-add report --confluence-labels-remove draft
+	pageLabelsCmd := &cobra.Command{
+		Use:   "labels",
+		Short: "Add and remove page labels",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Confluence page updating labels...")
+			common.Debug("Confluence", confluencePageOptions, stdout)
+			common.Debug("Confluence", confluenceLabelsOptions, stdout)
+
+			bytes, err := confluenceNew(stdout).UpdatePageLabels(confluencePageOptions, confluenceLabelsOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(confluenceOutput, "Confluence", []interface{}{confluenceOptions, confluencePageOptions, confluenceLabelsOptions}, bytes, stdout)
+		},
+	}
+	flags = pageLabelsCmd.PersistentFlags()
+	flags.StringSliceVar(&confluenceLabelsOptions.Add, "confluence-labels-add", confluenceLabelsOptions.Add, "Confluence page labels to add")
+	flags.StringSliceVar(&confluenceLabelsOptions.Remove, "confluence-labels-remove", confluenceLabelsOptions.Remove, "Confluence page labels to remove")
+	pageCmd.AddCommand(pageLabelsCmd)
+
+	return confluenceCmd
+}
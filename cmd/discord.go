@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/devopsext/tools/common"
+	"github.com/devopsext/tools/vendors"
+	"github.com/devopsext/utils"
+	"github.com/spf13/cobra"
+)
+
+var discordOptions = vendors.DiscordOptions{
+	Timeout:    envGet("DISCORD_TIMEOUT", 30).(int),
+	Insecure:   envGet("DISCORD_INSECURE", false).(bool),
+	WebhookURL: envGet("DISCORD_WEBHOOK_URL", "").(string),
+	Username:   envGet("DISCORD_USERNAME", "").(string),
+	AvatarURL:  envGet("DISCORD_AVATAR_URL", "").(string),
+	ThreadID:   envGet("DISCORD_THREAD", "").(string),
+	Title:      envGet("DISCORD_TITLE", "").(string),
+	Message:    envGet("DISCORD_MESSAGE", "").(string),
+	Severity:   envGet("DISCORD_SEVERITY", "").(string),
+	Color:      envGet("DISCORD_COLOR", "").(string),
+	ImageURL:   envGet("DISCORD_IMAGE_URL", "").(string),
+	Fields:     strings.Split(envGet("DISCORD_FIELDS", "").(string), ","),
+	FileName:   envGet("DISCORD_FILENAME", "").(string),
+	File:       envGet("DISCORD_FILE", "").(string),
+}
+
+var discordOutput = common.OutputOptions{
+	Output: envGet("DISCORD_OUTPUT", "").(string),
+	Query:  envGet("DISCORD_OUTPUT_QUERY", "").(string),
+}
+
+func discordNew(stdout *common.Stdout) *vendors.Discord {
+
+	common.Debug("Discord", discordOptions, stdout)
+	common.Debug("Discord", discordOutput, stdout)
+
+	messageBytes, err := utils.Content(discordOptions.Message)
+	if err != nil {
+		stdout.Panic(err)
+	}
+	discordOptions.Message = string(messageBytes)
+
+	if utils.IsEmpty(discordOptions.FileName) && utils.FileExists(discordOptions.File) {
+		discordOptions.FileName = filepath.Base(discordOptions.File)
+	}
+
+	fileBytes, err := utils.Content(discordOptions.File)
+	if err != nil {
+		stdout.Panic(err)
+	}
+	discordOptions.File = string(fileBytes)
+
+	return vendors.NewDiscord(discordOptions)
+}
+
+func NewDiscordCommand() *cobra.Command {
+
+	discordCmd := &cobra.Command{
+		Use:   "discord",
+		Short: "Discord tools",
+	}
+
+	flags := discordCmd.PersistentFlags()
+	flags.IntVar(&discordOptions.Timeout, "discord-timeout", discordOptions.Timeout, "Discord timeout")
+	flags.BoolVar(&discordOptions.Insecure, "discord-insecure", discordOptions.Insecure, "Discord insecure")
+	flags.StringVar(&discordOptions.WebhookURL, "discord-webhook-url", discordOptions.WebhookURL, "Discord webhook URL")
+	flags.StringVar(&discordOptions.Username, "discord-username", discordOptions.Username, "Discord username override")
+	flags.StringVar(&discordOptions.AvatarURL, "discord-avatar-url", discordOptions.AvatarURL, "Discord avatar url override")
+	flags.StringVar(&discordOptions.ThreadID, "discord-thread", discordOptions.ThreadID, "Discord thread ID")
+	flags.StringVar(&discordOptions.Title, "discord-title", discordOptions.Title, "Discord embed title")
+	flags.StringVar(&discordOptions.Message, "discord-message", discordOptions.Message, "Discord embed description")
+	flags.StringVar(&discordOptions.Severity, "discord-severity", discordOptions.Severity, "Discord severity to pick embed color: critical, error, warning, info, ok, resolved")
+	flags.StringVar(&discordOptions.Color, "discord-color", discordOptions.Color, "Discord embed color in hex format (#008000, overrides severity)")
+	flags.StringVar(&discordOptions.ImageURL, "discord-image-url", discordOptions.ImageURL, "Discord embed image url")
+	flags.StringSliceVar(&discordOptions.Fields, "discord-fields", discordOptions.Fields, "Discord embed fields (name=value)")
+	flags.StringVar(&discordOptions.FileName, "discord-filename", discordOptions.FileName, "Discord file name")
+	flags.StringVar(&discordOptions.File, "discord-file", discordOptions.File, "Discord file content or path")
+	flags.StringVar(&discordOutput.Output, "discord-output", discordOutput.Output, "Discord output")
+	flags.StringVar(&discordOutput.Query, "discord-output-query", discordOutput.Query, "Discord output query")
+
+	discordCmd.AddCommand(&cobra.Command{
+		Use:   "send-message",
+		Short: "Send text message",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Discord sending message...")
+			bytes, err := discordNew(stdout).SendMessage()
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(discordOutput, "Discord", []interface{}{discordOptions}, bytes, stdout)
+		},
+	})
+
+	discordCmd.AddCommand(&cobra.Command{
+		Use:   "send-file",
+		Short: "Send file",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Discord sending file...")
+			bytes, err := discordNew(stdout).SendFile()
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(discordOutput, "Discord", []interface{}{discordOptions}, bytes, stdout)
+		},
+	})
+
+	return discordCmd
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/devopsext/tools/common"
+	"github.com/devopsext/tools/vendors"
+	"github.com/devopsext/utils"
+	"github.com/spf13/cobra"
+)
+
+var emailOptions = vendors.EmailOptions{
+	Timeout:  envGet("EMAIL_TIMEOUT", 30).(int),
+	Insecure: envGet("EMAIL_INSECURE", false).(bool),
+	Host:     envGet("EMAIL_SMTP_HOST", "").(string),
+	Port:     envGet("EMAIL_SMTP_PORT", 25).(int),
+	User:     envGet("EMAIL_SMTP_USER", "").(string),
+	Password: envGet("EMAIL_SMTP_PASSWORD", "").(string),
+	Auth:     envGet("EMAIL_SMTP_AUTH", "plain").(string),
+	TLS:      envGet("EMAIL_SMTP_TLS", "auto").(string),
+}
+
+var emailSendOptions = vendors.EmailSendOptions{
+	From:         envGet("EMAIL_FROM", "").(string),
+	To:           strings.Split(envGet("EMAIL_TO", "").(string), ","),
+	Cc:           strings.Split(envGet("EMAIL_CC", "").(string), ","),
+	Bcc:          strings.Split(envGet("EMAIL_BCC", "").(string), ","),
+	Subject:      envGet("EMAIL_SUBJECT", "").(string),
+	Body:         envGet("EMAIL_BODY", "").(string),
+	Text:         envGet("EMAIL_TEXT", "").(string),
+	Attachments:  strings.Split(envGet("EMAIL_ATTACHMENTS", "").(string), ","),
+	InlineImages: strings.Split(envGet("EMAIL_INLINE_IMAGES", "").(string), ","),
+}
+
+var emailOutput = common.OutputOptions{
+	Output: envGet("EMAIL_OUTPUT", "").(string),
+	Query:  envGet("EMAIL_OUTPUT_QUERY", "").(string),
+}
+
+func emailNew(stdout *common.Stdout) *vendors.Email {
+
+	common.Debug("Email", emailOptions, stdout)
+	common.Debug("Email", emailOutput, stdout)
+
+	return vendors.NewEmail(emailOptions, stdout)
+}
+
+func NewEmailCommand() *cobra.Command {
+
+	emailCmd := &cobra.Command{
+		Use:   "email",
+		Short: "Email tools",
+	}
+
+	flags := emailCmd.PersistentFlags()
+	flags.IntVar(&emailOptions.Timeout, "email-timeout", emailOptions.Timeout, "Email timeout")
+	flags.BoolVar(&emailOptions.Insecure, "email-insecure", emailOptions.Insecure, "Email insecure")
+	flags.StringVar(&emailOptions.Host, "email-smtp-host", emailOptions.Host, "Email SMTP host")
+	flags.IntVar(&emailOptions.Port, "email-smtp-port", emailOptions.Port, "Email SMTP port")
+	flags.StringVar(&emailOptions.User, "email-smtp-user", emailOptions.User, "Email SMTP user")
+	flags.StringVar(&emailOptions.Password, "email-smtp-password", emailOptions.Password, "Email SMTP password")
+	flags.StringVar(&emailOptions.Auth, "email-smtp-auth", emailOptions.Auth, "Email SMTP auth: plain, login")
+	flags.StringVar(&emailOptions.TLS, "email-smtp-tls", emailOptions.TLS, "Email SMTP TLS: auto, none, starttls, tls")
+	flags.StringVar(&emailOutput.Output, "email-output", emailOutput.Output, "Email output")
+	flags.StringVar(&emailOutput.Query, "email-output-query", emailOutput.Query, "Email output query")
+
+	// tools email send --email-params --send-params
+	sendCmd := &cobra.Command{
+		Use:   "send",
+		Short: "Send email",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Email sending...")
+			common.Debug("Email", emailSendOptions, stdout)
+
+			bodyBytes, err := utils.Content(emailSendOptions.Body)
+			if err != nil {
+				stdout.Panic(err)
+			}
+			emailSendOptions.Body = string(bodyBytes)
+
+			textBytes, err := utils.Content(emailSendOptions.Text)
+			if err != nil {
+				stdout.Panic(err)
+			}
+			emailSendOptions.Text = string(textBytes)
+
+			bytes, err := emailNew(stdout).Send(emailSendOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(emailOutput, "Email", []interface{}{emailOptions, emailSendOptions}, bytes, stdout)
+		},
+	}
+	flags = sendCmd.PersistentFlags()
+	flags.StringVar(&emailSendOptions.From, "email-from", emailSendOptions.From, "Email from")
+	flags.StringSliceVar(&emailSendOptions.To, "email-to", emailSendOptions.To, "Email to")
+	flags.StringSliceVar(&emailSendOptions.Cc, "email-cc", emailSendOptions.Cc, "Email cc")
+	flags.StringSliceVar(&emailSendOptions.Bcc, "email-bcc", emailSendOptions.Bcc, "Email bcc")
+	flags.StringVar(&emailSendOptions.Subject, "email-subject", emailSendOptions.Subject, "Email subject")
+	flags.StringVar(&emailSendOptions.Body, "email-body", emailSendOptions.Body, "Email html body content or path")
+	flags.StringVar(&emailSendOptions.Text, "email-text", emailSendOptions.Text, "Email plain text content or path (generated from html body if empty)")
+	flags.StringSliceVar(&emailSendOptions.Attachments, "email-attachments", emailSendOptions.Attachments, "Email attachment paths")
+	flags.StringSliceVar(&emailSendOptions.InlineImages, "email-inline-images", emailSendOptions.InlineImages, "Email inline images (cid=path or path, referenced as cid:<name> in html body)")
+	emailCmd.AddCommand(sendCmd)
+
+	return emailCmd
+}
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/devopsext/tools/common"
@@ -10,14 +12,17 @@ import (
 )
 
 var grafanaOptions = vendors.GrafanaOptions{
-	URL:               envGet("GRAFANA_URL", "").(string),
-	Timeout:           envGet("GRAFANA_TIMEOUT", 30).(int),
-	Insecure:          envGet("GRAFANA_INSECURE", false).(bool),
-	APIKey:            envGet("GRAFANA_API_KEY", "").(string),
-	OrgID:             envGet("GRAFANA_ORG_ID", "1").(string),
-	DashboardUID:      envGet("GRAFANA_DASHBOARD_UID", "").(string),
-	DashboardSlug:     envGet("GRAFANA_DASHBOARD_SLUG", "").(string),
-	DashboardTimezone: envGet("GRAFANA_DASHBOARD_TIMEZONE", "UTC").(string),
+	URL:                 envGet("GRAFANA_URL", "").(string),
+	Timeout:             envGet("GRAFANA_TIMEOUT", 30).(int),
+	Insecure:            envGet("GRAFANA_INSECURE", false).(bool),
+	APIKey:              envGet("GRAFANA_API_KEY", "").(string),
+	ServiceAccountToken: envGet("GRAFANA_SERVICE_ACCOUNT_TOKEN", "").(string),
+	User:                envGet("GRAFANA_USER", "").(string),
+	Password:            envGet("GRAFANA_PASSWORD", "").(string),
+	OrgID:               envGet("GRAFANA_ORG_ID", "").(string),
+	DashboardUID:        envGet("GRAFANA_DASHBOARD_UID", "").(string),
+	DashboardSlug:       envGet("GRAFANA_DASHBOARD_SLUG", "").(string),
+	DashboardTimezone:   envGet("GRAFANA_DASHBOARD_TIMEZONE", "UTC").(string),
 }
 
 var grafanaCreateDashboardOptions = vendors.GrafanaCreateDahboardOptions{
@@ -32,41 +37,166 @@ var grafanaCreateDashboardOptions = vendors.GrafanaCreateDahboardOptions{
 		PanelIDs:    strings.Split(envGet("GRAFANA_DASHBOARD_CLONED_PANEL_IDS", "").(string), ","),
 		PanelTitles: strings.Split(envGet("GRAFANA_DASHBOARD_CLONED_PANEL_TITLES", "").(string), ","),
 		PanelSeries: strings.Split(envGet("GRAFANA_DASHBOARD_CLONED_PANEL_SERIES", "").(string), ","),
+		Vars:        common.RemoveEmptyStrings(strings.Split(envGet("GRAFANA_DASHBOARD_CLONED_VARS", "").(string), ",")),
+		VarsInline:  envGet("GRAFANA_DASHBOARD_CLONED_VARS_INLINE", false).(bool),
+		Datasources: common.RemoveEmptyStrings(strings.Split(envGet("GRAFANA_DASHBOARD_CLONED_DATASOURCE", "").(string), ",")),
 		LegendRight: envGet("GRAFANA_DASHBOARD_CLONED_LEGEND_RIGHT", false).(bool),
 		Arrange:     envGet("GRAFANA_DASHBOARD_CLONED_ARRANGE", false).(bool),
+		ArrangeMode: envGet("GRAFANA_DASHBOARD_CLONED_ARRANGE_MODE", vendors.GrafanaArrangeGrid).(string),
+		PanelSizes:  common.RemoveEmptyStrings(strings.Split(envGet("GRAFANA_DASHBOARD_CLONED_PANEL_SIZES", "").(string), ",")),
 		Count:       envGet("GRAFANA_DASHBOARD_CLONED_COUNT", 3).(int),
-		Width:       envGet("GRAFANA_DASHBOARD_CLONED_WIDTH", 6).(int),
+		Width:       envGet("GRAFANA_DASHBOARD_CLONED_WIDTH", 0).(int),
 		Height:      envGet("GRAFANA_DASHBOARD_CLONED_HEIGHT", 7).(int),
 	},
 }
 
 var grafanaRenderImageOptions = vendors.GrafanaRenderImageOptions{
-	PanelID: envGet("GRAFANA_IMAGE_PANEL_ID", "").(string),
-	From:    envGet("GRAFANA_IMAGE_FROM", "").(string),
-	To:      envGet("GRAFANA_IMAGE_TO", "").(string),
-	Width:   envGet("GRAFANA_IMAGE_WIDTH", 1280).(int),
-	Height:  envGet("GRAFANA_IMAGE_HEIGHT", 640).(int),
+	PanelID:       envGet("GRAFANA_IMAGE_PANEL_ID", "").(string),
+	PanelIDs:      common.RemoveEmptyStrings(strings.Split(envGet("GRAFANA_IMAGE_PANEL_IDS", "").(string), ",")),
+	PanelTitles:   common.RemoveEmptyStrings(strings.Split(envGet("GRAFANA_IMAGE_PANEL_TITLES", "").(string), ",")),
+	From:          envGet("GRAFANA_IMAGE_FROM", "").(string),
+	To:            envGet("GRAFANA_IMAGE_TO", "").(string),
+	Width:         envGet("GRAFANA_IMAGE_WIDTH", 1280).(int),
+	Height:        envGet("GRAFANA_IMAGE_HEIGHT", 640).(int),
+	Concurrency:   envGet("GRAFANA_IMAGE_CONCURRENCY", 4).(int),
+	Grid:          envGet("GRAFANA_IMAGE_GRID", false).(bool),
+	Columns:       envGet("GRAFANA_IMAGE_COLUMNS", 2).(int),
+	Captions:      envGet("GRAFANA_IMAGE_CAPTIONS", true).(bool),
+	FullDashboard: envGet("GRAFANA_IMAGE_FULL_DASHBOARD", false).(bool),
 }
 
 var grafanaGetAnnotationsOptions = vendors.GrafanaGetAnnotationsOptions{
-	Tags:        envGet("GRAFANA_ANNOTATION_TAGS", "").(string),
-	From:        envGet("GRAFANA_ANNOTATION_FROM", "").(string),
-	To:          envGet("GRAFANA_ANNOTATION_TO", "").(string),
-	Type:        envGet("GRAFANA_ANNOTATION_TYPE", "").(string),
-	Limit:       envGet("GRAFANA_ANNOTATION_LIMIT", 10).(int),
-	AlertID:     envGet("GRAFANA_ANNOTATION_ALERT_ID", 0).(int),
-	DashboardID: envGet("GRAFANA_ANNOTATION_DASHBOARD_ID", 0).(int),
-	PanelID:     envGet("GRAFANA_ANNOTATION_PANEL_ID", 0).(int),
-	MatchAny:    envGet("GRAFANA_ANNOTATION_MATCH_ANY", false).(bool),
+	Tags:         envGet("GRAFANA_ANNOTATION_TAGS", "").(string),
+	From:         envGet("GRAFANA_ANNOTATION_FROM", "").(string),
+	To:           envGet("GRAFANA_ANNOTATION_TO", "").(string),
+	Type:         envGet("GRAFANA_ANNOTATION_TYPE", "").(string),
+	Limit:        envGet("GRAFANA_ANNOTATION_LIMIT", 10).(int),
+	AlertID:      envGet("GRAFANA_ANNOTATION_ALERT_ID", 0).(int),
+	DashboardID:  envGet("GRAFANA_ANNOTATION_DASHBOARD_ID", 0).(int),
+	DashboardUID: envGet("GRAFANA_ANNOTATION_DASHBOARD_UID", "").(string),
+	PanelID:      envGet("GRAFANA_ANNOTATION_PANEL_ID", 0).(int),
+	MatchAny:     envGet("GRAFANA_ANNOTATION_MATCH_ANY", false).(bool),
 }
 
 var grafanaCreateAnnotationOptions = vendors.GrafanaCreateAnnotationOptions{
+	Time:         envGet("GRAFANA_ANNOTATION_TIME", "").(string),
+	TimeEnd:      envGet("GRAFANA_ANNOTATION_TIME_END", "").(string),
+	Tags:         envGet("GRAFANA_ANNOTATION_TAGS", "").(string),
+	Text:         envGet("GRAFANA_ANNOTATION_TEXT", "").(string),
+	DashboardUID: envGet("GRAFANA_ANNOTATION_DASHBOARD_UID", "").(string),
+	PanelID:      envGet("GRAFANA_ANNOTATION_PANEL_ID", 0).(int),
+}
+
+var grafanaUpdateAnnotationOptions = vendors.GrafanaUpdateAnnotationOptions{
+	ID:      envGet("GRAFANA_ANNOTATION_ID", 0).(int),
 	Time:    envGet("GRAFANA_ANNOTATION_TIME", "").(string),
 	TimeEnd: envGet("GRAFANA_ANNOTATION_TIME_END", "").(string),
 	Tags:    envGet("GRAFANA_ANNOTATION_TAGS", "").(string),
 	Text:    envGet("GRAFANA_ANNOTATION_TEXT", "").(string),
 }
 
+var grafanaDeleteAnnotationOptions = vendors.GrafanaDeleteAnnotationOptions{
+	ID: envGet("GRAFANA_ANNOTATION_ID", 0).(int),
+}
+
+var grafanaExportOptions = vendors.GrafanaExportOptions{
+	Folders: common.RemoveEmptyStrings(strings.Split(envGet("GRAFANA_EXPORT_FOLDERS", "").(string), ",")),
+	Dir:     envGet("GRAFANA_EXPORT_DIR", "").(string),
+}
+
+var grafanaImportOptions = vendors.GrafanaImportOptions{
+	Dir:         envGet("GRAFANA_IMPORT_DIR", "").(string),
+	FolderMap:   common.RemoveEmptyStrings(strings.Split(envGet("GRAFANA_IMPORT_FOLDER_MAP", "").(string), ",")),
+	Permissions: envGet("GRAFANA_IMPORT_PERMISSIONS", false).(bool),
+	Message:     envGet("GRAFANA_IMPORT_MESSAGE", "").(string),
+}
+
+var grafanaAlertRulesOptions = vendors.GrafanaAlertRulesOptions{
+	FolderUID: envGet("GRAFANA_ALERT_RULES_FOLDER_UID", "").(string),
+	Group:     envGet("GRAFANA_ALERT_RULES_GROUP", "").(string),
+	Format:    envGet("GRAFANA_ALERT_RULES_FORMAT", "json").(string),
+}
+
+var grafanaAlertRulesImportOptions = vendors.GrafanaAlertRulesImportOptions{
+	Input:     envGet("GRAFANA_ALERT_RULES_INPUT", "").(string),
+	FolderUID: envGet("GRAFANA_ALERT_RULES_FOLDER_UID", "").(string),
+}
+
+var grafanaContactPointsOptions = vendors.GrafanaContactPointsOptions{
+	Name:    envGet("GRAFANA_CONTACT_POINTS_NAME", "").(string),
+	Format:  envGet("GRAFANA_CONTACT_POINTS_FORMAT", "json").(string),
+	Decrypt: envGet("GRAFANA_CONTACT_POINTS_DECRYPT", false).(bool),
+}
+
+var grafanaContactPointsImportOptions = vendors.GrafanaContactPointsImportOptions{
+	Input: envGet("GRAFANA_CONTACT_POINTS_INPUT", "").(string),
+}
+
+var grafanaSilenceCreateOptions = vendors.GrafanaSilenceCreateOptions{
+	Matchers:  common.RemoveEmptyStrings(strings.Split(envGet("GRAFANA_SILENCE_MATCHERS", "").(string), ",")),
+	StartsAt:  envGet("GRAFANA_SILENCE_STARTS_AT", "").(string),
+	Duration:  envGet("GRAFANA_SILENCE_DURATION", "1h").(string),
+	CreatedBy: envGet("GRAFANA_SILENCE_CREATED_BY", "tools").(string),
+	Comment:   envGet("GRAFANA_SILENCE_COMMENT", "").(string),
+}
+
+var grafanaSilenceListOptions = vendors.GrafanaSilenceListOptions{
+	Matchers: common.RemoveEmptyStrings(strings.Split(envGet("GRAFANA_SILENCE_MATCHERS", "").(string), ",")),
+	State:    envGet("GRAFANA_SILENCE_STATE", "").(string),
+}
+
+var grafanaSilenceExpireOptions = vendors.GrafanaSilenceExpireOptions{
+	ID: envGet("GRAFANA_SILENCE_ID", "").(string),
+}
+
+var grafanaAlertsFiringOptions = vendors.GrafanaAlertsFiringOptions{
+	Matchers:  common.RemoveEmptyStrings(strings.Split(envGet("GRAFANA_ALERTS_MATCHERS", "").(string), ",")),
+	Silenced:  envGet("GRAFANA_ALERTS_SILENCED", false).(bool),
+	Inhibited: envGet("GRAFANA_ALERTS_INHIBITED", false).(bool),
+}
+
+var grafanaQueryOptions = vendors.GrafanaQueryOptions{
+	DatasourceUID: envGet("GRAFANA_QUERY_DATASOURCE_UID", "").(string),
+	Expr:          envGet("GRAFANA_QUERY_EXPR", "").(string),
+	Query:         envGet("GRAFANA_QUERY_JSON", "").(string),
+	From:          envGet("GRAFANA_QUERY_FROM", "now-1h").(string),
+	To:            envGet("GRAFANA_QUERY_TO", "now").(string),
+	Format:        envGet("GRAFANA_QUERY_FORMAT", "json").(string),
+	MaxDataPoints: envGet("GRAFANA_QUERY_MAX_DATA_POINTS", 0).(int),
+}
+
+var grafanaSnapshotOptions = vendors.GrafanaSnapshotOptions{
+	Name:    envGet("GRAFANA_SNAPSHOT_NAME", "").(string),
+	From:    envGet("GRAFANA_SNAPSHOT_FROM", "now-1h").(string),
+	To:      envGet("GRAFANA_SNAPSHOT_TO", "now").(string),
+	Expires: envGet("GRAFANA_SNAPSHOT_EXPIRES", "").(string),
+	Cloned: vendors.GrafanaClonedDahboardOptions{
+		UID:         envGet("GRAFANA_SNAPSHOT_CLONED_UID", "").(string),
+		PanelIDs:    common.RemoveEmptyStrings(strings.Split(envGet("GRAFANA_SNAPSHOT_CLONED_PANEL_IDS", "").(string), ",")),
+		PanelTitles: common.RemoveEmptyStrings(strings.Split(envGet("GRAFANA_SNAPSHOT_CLONED_PANEL_TITLES", "").(string), ",")),
+		Vars:        common.RemoveEmptyStrings(strings.Split(envGet("GRAFANA_SNAPSHOT_CLONED_VARS", "").(string), ",")),
+		Arrange:     envGet("GRAFANA_SNAPSHOT_CLONED_ARRANGE", false).(bool),
+	},
+}
+
+var grafanaShortURLOptions = vendors.GrafanaShortURLOptions{
+	PanelID: envGet("GRAFANA_SHORT_URL_PANEL_ID", "").(string),
+	From:    envGet("GRAFANA_SHORT_URL_FROM", "").(string),
+	To:      envGet("GRAFANA_SHORT_URL_TO", "").(string),
+	Vars:    common.RemoveEmptyStrings(strings.Split(envGet("GRAFANA_SHORT_URL_VARS", "").(string), ",")),
+}
+
+var grafanaOrgsOptions = vendors.GrafanaOrgsOptions{
+	Orgs: common.RemoveEmptyStrings(strings.Split(envGet("GRAFANA_ORGS", "").(string), ",")),
+}
+
+var grafanaDiffOptions = vendors.GrafanaDiffOptions{
+	AgainstUID:  envGet("GRAFANA_DIFF_AGAINST_UID", "").(string),
+	AgainstFile: envGet("GRAFANA_DIFF_AGAINST_FILE", "").(string),
+	Format:      envGet("GRAFANA_DIFF_FORMAT", vendors.GrafanaDiffFormatText).(string),
+	Ignore:      common.RemoveEmptyStrings(strings.Split(envGet("GRAFANA_DIFF_IGNORE", "").(string), ",")),
+}
+
 var grafanaOutput = common.OutputOptions{
 	Output: envGet("GRAFANA_OUTPUT", "").(string),
 	Query:  envGet("GRAFANA_OUTPUT_QUERY", "").(string),
@@ -79,6 +209,27 @@ func grafanaNew(stdout *common.Stdout) *vendors.Grafana {
 	return vendors.NewGrafana(grafanaOptions)
 }
 
+// grafanaRenderImages writes every rendered panel next to output as <name>-<panelID><ext>
+func grafanaRenderImages(stdout *common.Stdout) {
+
+	if utils.IsEmpty(grafanaOutput.Output) {
+		stdout.Error("grafana output is required to render several panels without grid")
+		return
+	}
+
+	images, err := grafanaNew(stdout).RenderImages(grafanaRenderImageOptions)
+	if err != nil {
+		stdout.Error(err)
+		return
+	}
+
+	ext := filepath.Ext(grafanaOutput.Output)
+	base := strings.TrimSuffix(grafanaOutput.Output, ext)
+	for _, image := range images {
+		common.OutputRaw(fmt.Sprintf("%s-%s%s", base, image.PanelID, ext), image.Image, stdout)
+	}
+}
+
 func NewGrafanaCommand() *cobra.Command {
 
 	grafanaCmd := cobra.Command{
@@ -90,8 +241,11 @@ func NewGrafanaCommand() *cobra.Command {
 	flags.StringVar(&grafanaOptions.URL, "grafana-url", grafanaOptions.URL, "Grafana URL")
 	flags.IntVar(&grafanaOptions.Timeout, "grafana-timeout", grafanaOptions.Timeout, "Grafana timeout")
 	flags.BoolVar(&grafanaOptions.Insecure, "grafana-insecure", grafanaOptions.Insecure, "Grafana insecure")
-	flags.StringVar(&grafanaOptions.APIKey, "grafana-api-key", grafanaOptions.APIKey, "Grafana api key")
-	flags.StringVar(&grafanaOptions.OrgID, "grafana-org-id", grafanaOptions.OrgID, "Grafana org id")
+	flags.StringVar(&grafanaOptions.APIKey, "grafana-api-key", grafanaOptions.APIKey, "Grafana api key (deprecated, use service account token)")
+	flags.StringVar(&grafanaOptions.ServiceAccountToken, "grafana-service-account-token", grafanaOptions.ServiceAccountToken, "Grafana service account token")
+	flags.StringVar(&grafanaOptions.User, "grafana-user", grafanaOptions.User, "Grafana user")
+	flags.StringVar(&grafanaOptions.Password, "grafana-password", grafanaOptions.Password, "Grafana password")
+	flags.StringVar(&grafanaOptions.OrgID, "grafana-org-id", grafanaOptions.OrgID, "Grafana org id (current org of credentials if empty)")
 	flags.StringVar(&grafanaOptions.DashboardUID, "grafana-dashboard-uid", grafanaOptions.DashboardUID, "Grafana dashboard uid")
 	flags.StringVar(&grafanaOptions.DashboardSlug, "grafana-dashboard-slug", grafanaOptions.DashboardSlug, "Grafana dashboard slug")
 	flags.StringVar(&grafanaOptions.DashboardTimezone, "grafana-dashboard-timezone", grafanaOptions.DashboardTimezone, "Grafana dashboard timezone")
@@ -130,13 +284,19 @@ func NewGrafanaCommand() *cobra.Command {
 	flags.StringSliceVar(&grafanaCreateDashboardOptions.Cloned.PanelIDs, "grafana-dashboard-cloned-panel-ids", grafanaCreateDashboardOptions.Cloned.PanelIDs, "Grafana dashboard cloned panel ids")
 	flags.StringSliceVar(&grafanaCreateDashboardOptions.Cloned.PanelTitles, "grafana-dashboard-cloned-panel-titles", grafanaCreateDashboardOptions.Cloned.PanelTitles, "Grafana dashboard cloned panel titles")
 	flags.StringSliceVar(&grafanaCreateDashboardOptions.Cloned.PanelSeries, "grafana-dashboard-cloned-panel-series", grafanaCreateDashboardOptions.Cloned.PanelSeries, "Grafana dashboard cloned panel series")
+	flags.StringSliceVar(&grafanaCreateDashboardOptions.Cloned.Vars, "grafana-dashboard-cloned-vars", grafanaCreateDashboardOptions.Cloned.Vars, "Grafana dashboard cloned variable values as name=value")
+	flags.BoolVar(&grafanaCreateDashboardOptions.Cloned.VarsInline, "grafana-dashboard-cloned-vars-inline", grafanaCreateDashboardOptions.Cloned.VarsInline, "Grafana dashboard cloned variables inlined into queries instead of pinned")
+	flags.StringSliceVar(&grafanaCreateDashboardOptions.Cloned.Datasources, "grafana-dashboard-cloned-datasource", grafanaCreateDashboardOptions.Cloned.Datasources, "Grafana dashboard cloned datasource remap as old=new")
 	flags.BoolVar(&grafanaCreateDashboardOptions.Cloned.LegendRight, "grafana-dashboard-cloned-legend-right", grafanaCreateDashboardOptions.Cloned.LegendRight, "Grafana dashboard cloned legend right")
 	flags.BoolVar(&grafanaCreateDashboardOptions.Cloned.Arrange, "grafana-dashboard-cloned-arrange", grafanaCreateDashboardOptions.Cloned.Arrange, "Grafana dashboard cloned arrange")
+	flags.StringVar(&grafanaCreateDashboardOptions.Cloned.ArrangeMode, "grafana-dashboard-cloned-arrange-mode", grafanaCreateDashboardOptions.Cloned.ArrangeMode, "Grafana dashboard cloned arrange mode: keep, grid, row")
+	flags.StringSliceVar(&grafanaCreateDashboardOptions.Cloned.PanelSizes, "grafana-dashboard-cloned-panel-sizes", grafanaCreateDashboardOptions.Cloned.PanelSizes, "Grafana dashboard cloned panel sizes as <id or title>=<width>x<height>")
 	flags.IntVar(&grafanaCreateDashboardOptions.Cloned.Count, "grafana-dashboard-cloned-count", grafanaCreateDashboardOptions.Cloned.Count, "Grafana dashboard cloned count per line")
-	flags.IntVar(&grafanaCreateDashboardOptions.Cloned.Width, "grafana-dashboard-cloned-width", grafanaCreateDashboardOptions.Cloned.Width, "Grafana dashboard cloned width")
+	flags.IntVar(&grafanaCreateDashboardOptions.Cloned.Width, "grafana-dashboard-cloned-width", grafanaCreateDashboardOptions.Cloned.Width, "Grafana dashboard cloned width, 0 is 24 divided by count")
 	flags.IntVar(&grafanaCreateDashboardOptions.Cloned.Height, "grafana-dashboard-cloned-height", grafanaCreateDashboardOptions.Cloned.Height, "Grafana dashboard cloned height")
 	grafanaCmd.AddCommand(&createDashboardCmd)
 
+	// tools grafana render-image --grafana-image-panel-ids 2,4 --grafana-image-panel-titles "^CPU" --grafana-image-grid
 	renderImageCmd := cobra.Command{
 		Use:   "render-image",
 		Short: "Render image",
@@ -144,6 +304,12 @@ func NewGrafanaCommand() *cobra.Command {
 			stdout.Debug("Grafana rendering image...")
 			common.Debug("Grafana", grafanaRenderImageOptions, stdout)
 
+			if !grafanaRenderImageOptions.FullDashboard && !grafanaRenderImageOptions.Grid &&
+				(len(grafanaRenderImageOptions.PanelIDs) > 0 || len(grafanaRenderImageOptions.PanelTitles) > 0) {
+				grafanaRenderImages(stdout)
+				return
+			}
+
 			bytes, err := grafanaNew(stdout).RenderImage(grafanaRenderImageOptions)
 			if err != nil {
 				stdout.Error(err)
@@ -158,6 +324,14 @@ func NewGrafanaCommand() *cobra.Command {
 	flags.StringVar(&grafanaRenderImageOptions.To, "grafana-image-to", grafanaRenderImageOptions.To, "Grafana image to")
 	flags.IntVar(&grafanaRenderImageOptions.Width, "grafana-image-width", grafanaRenderImageOptions.Width, "Grafana image width")
 	flags.IntVar(&grafanaRenderImageOptions.Height, "grafana-image-height", grafanaRenderImageOptions.Height, "Grafana image height")
+	flags.StringSliceVar(&grafanaRenderImageOptions.PanelIDs, "grafana-image-panel-ids", grafanaRenderImageOptions.PanelIDs, "Grafana image panel ids")
+	flags.StringSliceVar(&grafanaRenderImageOptions.PanelTitles, "grafana-image-panel-titles", grafanaRenderImageOptions.PanelTitles, "Grafana image panel titles (regexp)")
+	flags.IntVar(&grafanaRenderImageOptions.Concurrency, "grafana-image-concurrency", grafanaRenderImageOptions.Concurrency, "Grafana image concurrent renders")
+	flags.BoolVar(&grafanaRenderImageOptions.Grid, "grafana-image-grid", grafanaRenderImageOptions.Grid, "Grafana image compose panels into one grid")
+	flags.IntVar(&grafanaRenderImageOptions.Columns, "grafana-image-columns", grafanaRenderImageOptions.Columns, "Grafana image grid columns")
+	flags.BoolVar(&grafanaRenderImageOptions.Captions, "grafana-image-captions", grafanaRenderImageOptions.Captions, "Grafana image grid captions")
+	flags.BoolVar(&grafanaRenderImageOptions.FullDashboard, "grafana-image-full-dashboard", grafanaRenderImageOptions.FullDashboard, "Grafana image whole dashboard")
+	flags.BoolVar(&grafanaRenderImageOptions.FullDashboard, "full-dashboard", grafanaRenderImageOptions.FullDashboard, "Grafana image whole dashboard (short for --grafana-image-full-dashboard)")
 	grafanaCmd.AddCommand(&renderImageCmd)
 
 	getDashboardCmd := cobra.Command{
@@ -199,6 +373,7 @@ func NewGrafanaCommand() *cobra.Command {
 	flags.IntVar(&grafanaGetAnnotationsOptions.Limit, "grafana-annotation-limit", grafanaGetAnnotationsOptions.Limit, "Grafana annotations limit (default: 10)")
 	flags.IntVar(&grafanaGetAnnotationsOptions.AlertID, "grafana-annotation-alert", grafanaGetAnnotationsOptions.AlertID, "Grafana annotations alert")
 	flags.IntVar(&grafanaGetAnnotationsOptions.DashboardID, "grafana-annotation-dashboard", grafanaGetAnnotationsOptions.DashboardID, "Grafana annotations dashboard")
+	flags.StringVar(&grafanaGetAnnotationsOptions.DashboardUID, "grafana-annotation-dashboard-uid", grafanaGetAnnotationsOptions.DashboardUID, "Grafana annotations dashboard uid")
 	flags.IntVar(&grafanaGetAnnotationsOptions.PanelID, "grafana-annotation-panel", grafanaGetAnnotationsOptions.PanelID, "Grafana annotations panel")
 	flags.BoolVar(&grafanaGetAnnotationsOptions.MatchAny, "grafana-annotation-match-any", grafanaGetAnnotationsOptions.MatchAny, "Grafana annotations match any tag")
 	grafanaCmd.AddCommand(&getAnnotationsCmd)
@@ -226,9 +401,571 @@ func NewGrafanaCommand() *cobra.Command {
 	flags = createAnnotationCmd.PersistentFlags()
 	flags.StringVar(&grafanaCreateAnnotationOptions.Text, "grafana-annotation-text", grafanaCreateAnnotationOptions.Text, "Grafana annotation text")
 	flags.StringVar(&grafanaCreateAnnotationOptions.Time, "grafana-annotation-time", grafanaCreateAnnotationOptions.Time, "Grafana annotation time")
-	flags.StringVar(&grafanaCreateAnnotationOptions.TimeEnd, "grafana-annotation-time-end", grafanaCreateAnnotationOptions.Tags, "Grafana annotation end time")
+	flags.StringVar(&grafanaCreateAnnotationOptions.TimeEnd, "grafana-annotation-time-end", grafanaCreateAnnotationOptions.TimeEnd, "Grafana annotation end time")
 	flags.StringVar(&grafanaCreateAnnotationOptions.Tags, "grafana-annotation-tags", grafanaCreateAnnotationOptions.Tags, "Grafana annotation tags (comma separated)")
+	flags.StringVar(&grafanaCreateAnnotationOptions.DashboardUID, "grafana-annotation-dashboard-uid", grafanaCreateAnnotationOptions.DashboardUID, "Grafana annotation dashboard uid")
+	flags.IntVar(&grafanaCreateAnnotationOptions.PanelID, "grafana-annotation-panel", grafanaCreateAnnotationOptions.PanelID, "Grafana annotation panel")
 	grafanaCmd.AddCommand(&createAnnotationCmd)
 
+	// tools grafana update-annotation --grafana-annotation-id 42 --grafana-annotation-time-end now
+	updateAnnotationCmd := cobra.Command{
+		Use:   "update-annotation",
+		Short: "Update grafana annotation",
+		Run: func(cmd *cobra.Command, args []string) {
+			stdout.Debug("Grafana updating annotation...")
+			common.Debug("Grafana", grafanaUpdateAnnotationOptions, stdout)
+
+			bytes, err := grafanaNew(stdout).UpdateAnnotation(grafanaUpdateAnnotationOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions, grafanaUpdateAnnotationOptions}, bytes, stdout)
+		},
+	}
+	flags = updateAnnotationCmd.PersistentFlags()
+	flags.IntVar(&grafanaUpdateAnnotationOptions.ID, "grafana-annotation-id", grafanaUpdateAnnotationOptions.ID, "Grafana annotation id")
+	flags.StringVar(&grafanaUpdateAnnotationOptions.Text, "grafana-annotation-text", grafanaUpdateAnnotationOptions.Text, "Grafana annotation text")
+	flags.StringVar(&grafanaUpdateAnnotationOptions.Time, "grafana-annotation-time", grafanaUpdateAnnotationOptions.Time, "Grafana annotation time")
+	flags.StringVar(&grafanaUpdateAnnotationOptions.TimeEnd, "grafana-annotation-time-end", grafanaUpdateAnnotationOptions.TimeEnd, "Grafana annotation end time, RFC3339 or now to end region")
+	flags.StringVar(&grafanaUpdateAnnotationOptions.Tags, "grafana-annotation-tags", grafanaUpdateAnnotationOptions.Tags, "Grafana annotation tags (comma separated)")
+	grafanaCmd.AddCommand(&updateAnnotationCmd)
+
+	// tools grafana delete-annotation --grafana-annotation-id 42
+	deleteAnnotationCmd := cobra.Command{
+		Use:   "delete-annotation",
+		Short: "Delete grafana annotation",
+		Run: func(cmd *cobra.Command, args []string) {
+			stdout.Debug("Grafana deleting annotation...")
+			common.Debug("Grafana", grafanaDeleteAnnotationOptions, stdout)
+
+			bytes, err := grafanaNew(stdout).DeleteAnnotation(grafanaDeleteAnnotationOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions, grafanaDeleteAnnotationOptions}, bytes, stdout)
+		},
+	}
+	flags = deleteAnnotationCmd.PersistentFlags()
+	flags.IntVar(&grafanaDeleteAnnotationOptions.ID, "grafana-annotation-id", grafanaDeleteAnnotationOptions.ID, "Grafana annotation id")
+	grafanaCmd.AddCommand(&deleteAnnotationCmd)
+
+	// tools grafana export --grafana-params --folder Platform --dir ./dashboards
+	exportCmd := cobra.Command{
+		Use:   "export",
+		Short: "Export dashboards and folders to directory",
+		Run: func(cmd *cobra.Command, args []string) {
+			stdout.Debug("Grafana exporting dashboards...")
+			common.Debug("Grafana", grafanaExportOptions, stdout)
+
+			bytes, err := grafanaNew(stdout).ExportDashboards(grafanaExportOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions, grafanaExportOptions}, bytes, stdout)
+		},
+	}
+	flags = exportCmd.PersistentFlags()
+	flags.StringSliceVar(&grafanaExportOptions.Folders, "grafana-export-folder", grafanaExportOptions.Folders, "Grafana export folder uid or title (all if empty)")
+	flags.StringSliceVar(&grafanaExportOptions.Folders, "folder", grafanaExportOptions.Folders, "Grafana export folder (short for --grafana-export-folder)")
+	flags.StringVar(&grafanaExportOptions.Dir, "grafana-export-dir", grafanaExportOptions.Dir, "Grafana export directory")
+	flags.StringVar(&grafanaExportOptions.Dir, "dir", grafanaExportOptions.Dir, "Grafana export directory (short for --grafana-export-dir)")
+	grafanaCmd.AddCommand(&exportCmd)
+
+	// tools grafana import --grafana-params --dir ./dashboards --grafana-import-folder-map platform=platform-staging
+	importCmd := cobra.Command{
+		Use:   "import",
+		Short: "Import dashboards and folders from directory",
+		Run: func(cmd *cobra.Command, args []string) {
+			stdout.Debug("Grafana importing dashboards...")
+			common.Debug("Grafana", grafanaImportOptions, stdout)
+
+			bytes, err := grafanaNew(stdout).ImportDashboards(grafanaImportOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions, grafanaImportOptions}, bytes, stdout)
+		},
+	}
+	flags = importCmd.PersistentFlags()
+	flags.StringVar(&grafanaImportOptions.Dir, "grafana-import-dir", grafanaImportOptions.Dir, "Grafana import directory")
+	flags.StringVar(&grafanaImportOptions.Dir, "dir", grafanaImportOptions.Dir, "Grafana import directory (short for --grafana-import-dir)")
+	flags.StringSliceVar(&grafanaImportOptions.FolderMap, "grafana-import-folder-map", grafanaImportOptions.FolderMap, "Grafana import folder map source=target folder uid")
+	flags.BoolVar(&grafanaImportOptions.Permissions, "grafana-import-permissions", grafanaImportOptions.Permissions, "Grafana import folder and dashboard permissions")
+	flags.StringVar(&grafanaImportOptions.Message, "grafana-import-message", grafanaImportOptions.Message, "Grafana import dashboard version message")
+	grafanaCmd.AddCommand(&importCmd)
+
+	// tools grafana diff --grafana-params --grafana-dashboard-uid A --against-file dashboards/plat/A.json
+	diffCmd := cobra.Command{
+		Use:   "diff",
+		Short: "Diff dashboard against another dashboard or file",
+		Run: func(cmd *cobra.Command, args []string) {
+			stdout.Debug("Grafana diffing dashboards...")
+			common.Debug("Grafana", grafanaDiffOptions, stdout)
+
+			if !utils.IsEmpty(grafanaDiffOptions.AgainstFile) {
+				fileBytes, err := utils.Content(grafanaDiffOptions.AgainstFile)
+				if err != nil {
+					stdout.Panic(err)
+				}
+				grafanaDiffOptions.AgainstFile = string(fileBytes)
+			}
+
+			bytes, err := grafanaNew(stdout).DiffDashboards(grafanaDiffOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			if grafanaDiffOptions.Format == vendors.GrafanaDiffFormatChanges {
+				common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions, grafanaDiffOptions}, bytes, stdout)
+				return
+			}
+			common.OutputRaw(grafanaOutput.Output, bytes, stdout)
+		},
+	}
+	flags = diffCmd.PersistentFlags()
+	flags.StringVar(&grafanaDiffOptions.AgainstUID, "grafana-diff-against-uid", grafanaDiffOptions.AgainstUID, "Grafana diff against dashboard uid")
+	flags.StringVar(&grafanaDiffOptions.AgainstUID, "against-uid", grafanaDiffOptions.AgainstUID, "Grafana diff against dashboard uid (short for --grafana-diff-against-uid)")
+	flags.StringVar(&grafanaDiffOptions.AgainstFile, "grafana-diff-against-file", grafanaDiffOptions.AgainstFile, "Grafana diff against dashboard file")
+	flags.StringVar(&grafanaDiffOptions.AgainstFile, "against-file", grafanaDiffOptions.AgainstFile, "Grafana diff against dashboard file (short for --grafana-diff-against-file)")
+	flags.StringVar(&grafanaDiffOptions.Format, "grafana-diff-format", grafanaDiffOptions.Format, "Grafana diff format: text, changes (json list with pointers, old and new values, not a JSON patch)")
+	flags.StringSliceVar(&grafanaDiffOptions.Ignore, "grafana-diff-ignore", grafanaDiffOptions.Ignore, "Grafana diff additional fields to ignore")
+	grafanaCmd.AddCommand(&diffCmd)
+
+	// tools grafana query --grafana-params --datasource-uid prom --expr 'sum(up) by (job)' --from now-6h --grafana-query-format csv
+	queryCmd := cobra.Command{
+		Use:   "query",
+		Short: "Query datasource through grafana",
+		Run: func(cmd *cobra.Command, args []string) {
+			stdout.Debug("Grafana querying datasource...")
+			common.Debug("Grafana", grafanaQueryOptions, stdout)
+
+			bytes, err := grafanaNew(stdout).Query(grafanaQueryOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			if strings.EqualFold(grafanaQueryOptions.Format, "csv") {
+				common.OutputRaw(grafanaOutput.Output, bytes, stdout)
+				return
+			}
+			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions, grafanaQueryOptions}, bytes, stdout)
+		},
+	}
+	flags = queryCmd.PersistentFlags()
+	flags.StringVar(&grafanaQueryOptions.DatasourceUID, "grafana-query-datasource-uid", grafanaQueryOptions.DatasourceUID, "Grafana query datasource uid")
+	flags.StringVar(&grafanaQueryOptions.DatasourceUID, "datasource-uid", grafanaQueryOptions.DatasourceUID, "Grafana query datasource uid (short for --grafana-query-datasource-uid)")
+	flags.StringVar(&grafanaQueryOptions.Expr, "grafana-query-expr", grafanaQueryOptions.Expr, "Grafana query expression")
+	flags.StringVar(&grafanaQueryOptions.Expr, "expr", grafanaQueryOptions.Expr, "Grafana query expression (short for --grafana-query-expr)")
+	flags.StringVar(&grafanaQueryOptions.Query, "grafana-query-json", grafanaQueryOptions.Query, "Grafana query additional fields json, content or path")
+	flags.StringVar(&grafanaQueryOptions.From, "grafana-query-from", grafanaQueryOptions.From, "Grafana query from, relative or RFC3339")
+	flags.StringVar(&grafanaQueryOptions.From, "from", grafanaQueryOptions.From, "Grafana query from (short for --grafana-query-from)")
+	flags.StringVar(&grafanaQueryOptions.To, "grafana-query-to", grafanaQueryOptions.To, "Grafana query to, relative or RFC3339")
+	flags.StringVar(&grafanaQueryOptions.To, "to", grafanaQueryOptions.To, "Grafana query to (short for --grafana-query-to)")
+	flags.StringVar(&grafanaQueryOptions.Format, "grafana-query-format", grafanaQueryOptions.Format, "Grafana query output format: json, csv")
+	flags.IntVar(&grafanaQueryOptions.MaxDataPoints, "grafana-query-max-data-points", grafanaQueryOptions.MaxDataPoints, "Grafana query max data points")
+	grafanaCmd.AddCommand(&queryCmd)
+
+	snapshotCmd := cobra.Command{
+		Use:   "snapshot",
+		Short: "Snapshot methods",
+	}
+	grafanaCmd.AddCommand(&snapshotCmd)
+
+	// tools grafana snapshot create --grafana-params --grafana-dashboard-uid api --grafana-snapshot-from now-6h --grafana-snapshot-expires 168h
+	snapshotCreateCmd := cobra.Command{
+		Use:   "create",
+		Short: "Create dashboard snapshot",
+		Run: func(cmd *cobra.Command, args []string) {
+			stdout.Debug("Grafana creating snapshot...")
+			common.Debug("Grafana", grafanaSnapshotOptions, stdout)
+
+			bytes, err := grafanaNew(stdout).CreateSnapshot(grafanaSnapshotOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions, grafanaSnapshotOptions}, bytes, stdout)
+		},
+	}
+	flags = snapshotCreateCmd.PersistentFlags()
+	flags.StringVar(&grafanaSnapshotOptions.Name, "grafana-snapshot-name", grafanaSnapshotOptions.Name, "Grafana snapshot name (default dashboard title)")
+	flags.StringVar(&grafanaSnapshotOptions.From, "grafana-snapshot-from", grafanaSnapshotOptions.From, "Grafana snapshot from, relative or RFC3339")
+	flags.StringVar(&grafanaSnapshotOptions.To, "grafana-snapshot-to", grafanaSnapshotOptions.To, "Grafana snapshot to, relative or RFC3339")
+	flags.StringVar(&grafanaSnapshotOptions.Expires, "grafana-snapshot-expires", grafanaSnapshotOptions.Expires, "Grafana snapshot expires after duration (default never)")
+	flags.StringVar(&grafanaSnapshotOptions.Cloned.UID, "grafana-snapshot-cloned-uid", grafanaSnapshotOptions.Cloned.UID, "Grafana snapshot of board cloned from dashboard uid")
+	flags.StringSliceVar(&grafanaSnapshotOptions.Cloned.PanelIDs, "grafana-snapshot-cloned-panel-ids", grafanaSnapshotOptions.Cloned.PanelIDs, "Grafana snapshot cloned panel ids")
+	flags.StringSliceVar(&grafanaSnapshotOptions.Cloned.PanelTitles, "grafana-snapshot-cloned-panel-titles", grafanaSnapshotOptions.Cloned.PanelTitles, "Grafana snapshot cloned panel titles")
+	flags.StringSliceVar(&grafanaSnapshotOptions.Cloned.Vars, "grafana-snapshot-cloned-vars", grafanaSnapshotOptions.Cloned.Vars, "Grafana snapshot cloned variable values as name=value")
+	flags.BoolVar(&grafanaSnapshotOptions.Cloned.Arrange, "grafana-snapshot-cloned-arrange", grafanaSnapshotOptions.Cloned.Arrange, "Grafana snapshot cloned arrange")
+	snapshotCmd.AddCommand(&snapshotCreateCmd)
+
+	// tools grafana short-url --grafana-params --grafana-dashboard-uid api --grafana-short-url-panel-id 2 --grafana-short-url-vars cluster=prod
+	shortURLCmd := cobra.Command{
+		Use:   "short-url",
+		Short: "Create short url to dashboard or panel",
+		Run: func(cmd *cobra.Command, args []string) {
+			stdout.Debug("Grafana creating short url...")
+			common.Debug("Grafana", grafanaShortURLOptions, stdout)
+
+			bytes, err := grafanaNew(stdout).CreateShortURL(grafanaShortURLOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions, grafanaShortURLOptions}, bytes, stdout)
+		},
+	}
+	flags = shortURLCmd.PersistentFlags()
+	flags.StringVar(&grafanaShortURLOptions.PanelID, "grafana-short-url-panel-id", grafanaShortURLOptions.PanelID, "Grafana short url panel id")
+	flags.StringVar(&grafanaShortURLOptions.From, "grafana-short-url-from", grafanaShortURLOptions.From, "Grafana short url from, relative or RFC3339")
+	flags.StringVar(&grafanaShortURLOptions.To, "grafana-short-url-to", grafanaShortURLOptions.To, "Grafana short url to, relative or RFC3339")
+	flags.StringSliceVar(&grafanaShortURLOptions.Vars, "grafana-short-url-vars", grafanaShortURLOptions.Vars, "Grafana short url variable values as name=value")
+	grafanaCmd.AddCommand(&shortURLCmd)
+
+	alertRulesCmd := cobra.Command{
+		Use:   "alert-rules",
+		Short: "Alert rules methods",
+	}
+	grafanaCmd.AddCommand(&alertRulesCmd)
+
+	// tools grafana alert-rules list --grafana-params --grafana-alert-rules-folder-uid platform
+	alertRulesListCmd := cobra.Command{
+		Use:   "list",
+		Short: "List provisioned alert rules",
+		Run: func(cmd *cobra.Command, args []string) {
+			stdout.Debug("Grafana listing alert rules...")
+			common.Debug("Grafana", grafanaAlertRulesOptions, stdout)
+
+			bytes, err := grafanaNew(stdout).GetAlertRules(grafanaAlertRulesOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions, grafanaAlertRulesOptions}, bytes, stdout)
+		},
+	}
+	flags = alertRulesListCmd.PersistentFlags()
+	flags.StringVar(&grafanaAlertRulesOptions.FolderUID, "grafana-alert-rules-folder-uid", grafanaAlertRulesOptions.FolderUID, "Grafana alert rules folder uid")
+	flags.StringVar(&grafanaAlertRulesOptions.Group, "grafana-alert-rules-group", grafanaAlertRulesOptions.Group, "Grafana alert rules group")
+	alertRulesCmd.AddCommand(&alertRulesListCmd)
+
+	// tools grafana alert-rules export --grafana-params --grafana-alert-rules-format yaml --grafana-output rules.yaml
+	alertRulesExportCmd := cobra.Command{
+		Use:   "export",
+		Short: "Export alert rules in provisioning file format",
+		Run: func(cmd *cobra.Command, args []string) {
+			stdout.Debug("Grafana exporting alert rules...")
+			common.Debug("Grafana", grafanaAlertRulesOptions, stdout)
+
+			bytes, err := grafanaNew(stdout).ExportAlertRules(grafanaAlertRulesOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputRaw(grafanaOutput.Output, bytes, stdout)
+		},
+	}
+	flags = alertRulesExportCmd.PersistentFlags()
+	flags.StringVar(&grafanaAlertRulesOptions.FolderUID, "grafana-alert-rules-folder-uid", grafanaAlertRulesOptions.FolderUID, "Grafana alert rules folder uid")
+	flags.StringVar(&grafanaAlertRulesOptions.Group, "grafana-alert-rules-group", grafanaAlertRulesOptions.Group, "Grafana alert rules group")
+	flags.StringVar(&grafanaAlertRulesOptions.Format, "grafana-alert-rules-format", grafanaAlertRulesOptions.Format, "Grafana alert rules export format: json, yaml, hcl")
+	alertRulesCmd.AddCommand(&alertRulesExportCmd)
+
+	// tools grafana alert-rules import --grafana-params --grafana-alert-rules-input rules.json
+	alertRulesImportCmd := cobra.Command{
+		Use:   "import",
+		Short: "Import alert rules creating or updating them by uid",
+		Run: func(cmd *cobra.Command, args []string) {
+			stdout.Debug("Grafana importing alert rules...")
+			common.Debug("Grafana", grafanaAlertRulesImportOptions, stdout)
+
+			bytes, err := grafanaNew(stdout).ImportAlertRules(grafanaAlertRulesImportOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions, grafanaAlertRulesImportOptions}, bytes, stdout)
+		},
+	}
+	flags = alertRulesImportCmd.PersistentFlags()
+	flags.StringVar(&grafanaAlertRulesImportOptions.Input, "grafana-alert-rules-input", grafanaAlertRulesImportOptions.Input, "Grafana alert rules json as listed or exported, content or path")
+	flags.StringVar(&grafanaAlertRulesImportOptions.FolderUID, "grafana-alert-rules-folder-uid", grafanaAlertRulesImportOptions.FolderUID, "Grafana alert rules folder uid to import into")
+	alertRulesCmd.AddCommand(&alertRulesImportCmd)
+
+	contactPointsCmd := cobra.Command{
+		Use:   "contact-points",
+		Short: "Contact points methods",
+	}
+	grafanaCmd.AddCommand(&contactPointsCmd)
+
+	// tools grafana contact-points list --grafana-params --grafana-contact-points-name oncall
+	contactPointsListCmd := cobra.Command{
+		Use:   "list",
+		Short: "List contact points",
+		Run: func(cmd *cobra.Command, args []string) {
+			stdout.Debug("Grafana listing contact points...")
+			common.Debug("Grafana", grafanaContactPointsOptions, stdout)
+
+			bytes, err := grafanaNew(stdout).GetContactPoints(grafanaContactPointsOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions, grafanaContactPointsOptions}, bytes, stdout)
+		},
+	}
+	flags = contactPointsListCmd.PersistentFlags()
+	flags.StringVar(&grafanaContactPointsOptions.Name, "grafana-contact-points-name", grafanaContactPointsOptions.Name, "Grafana contact points name")
+	contactPointsCmd.AddCommand(&contactPointsListCmd)
+
+	// tools grafana contact-points export --grafana-params --grafana-contact-points-decrypt --grafana-output contact-points.json
+	contactPointsExportCmd := cobra.Command{
+		Use:   "export",
+		Short: "Export contact points in provisioning file format",
+		Run: func(cmd *cobra.Command, args []string) {
+			stdout.Debug("Grafana exporting contact points...")
+			common.Debug("Grafana", grafanaContactPointsOptions, stdout)
+
+			bytes, err := grafanaNew(stdout).ExportContactPoints(grafanaContactPointsOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputRaw(grafanaOutput.Output, bytes, stdout)
+		},
+	}
+	flags = contactPointsExportCmd.PersistentFlags()
+	flags.StringVar(&grafanaContactPointsOptions.Name, "grafana-contact-points-name", grafanaContactPointsOptions.Name, "Grafana contact points name")
+	flags.StringVar(&grafanaContactPointsOptions.Format, "grafana-contact-points-format", grafanaContactPointsOptions.Format, "Grafana contact points export format: json, yaml, hcl")
+	flags.BoolVar(&grafanaContactPointsOptions.Decrypt, "grafana-contact-points-decrypt", grafanaContactPointsOptions.Decrypt, "Grafana contact points export secure settings, they are redacted otherwise")
+	contactPointsCmd.AddCommand(&contactPointsExportCmd)
+
+	// tools grafana contact-points import --grafana-params --grafana-contact-points-input contact-points.json
+	contactPointsImportCmd := cobra.Command{
+		Use:   "import",
+		Short: "Import contact points, existing ones are updated by uid",
+		Run: func(cmd *cobra.Command, args []string) {
+			stdout.Debug("Grafana importing contact points...")
+			common.Debug("Grafana", grafanaContactPointsImportOptions, stdout)
+
+			bytes, err := grafanaNew(stdout).ImportContactPoints(grafanaContactPointsImportOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions, grafanaContactPointsImportOptions}, bytes, stdout)
+		},
+	}
+	flags = contactPointsImportCmd.PersistentFlags()
+	flags.StringVar(&grafanaContactPointsImportOptions.Input, "grafana-contact-points-input", grafanaContactPointsImportOptions.Input, "Grafana contact points json as listed or exported, content or path")
+	contactPointsCmd.AddCommand(&contactPointsImportCmd)
+
+	silenceCmd := cobra.Command{
+		Use:   "silence",
+		Short: "Silence methods",
+	}
+	grafanaCmd.AddCommand(&silenceCmd)
+
+	// tools grafana silence create --grafana-params --grafana-silence-matchers alertname=HighCPU,env=~prod.* --grafana-silence-duration 2h --grafana-silence-comment maintenance
+	silenceCreateCmd := cobra.Command{
+		Use:   "create",
+		Short: "Create silence",
+		Run: func(cmd *cobra.Command, args []string) {
+			stdout.Debug("Grafana creating silence...")
+			common.Debug("Grafana", grafanaSilenceCreateOptions, stdout)
+
+			bytes, err := grafanaNew(stdout).CreateSilence(grafanaSilenceCreateOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions, grafanaSilenceCreateOptions}, bytes, stdout)
+		},
+	}
+	flags = silenceCreateCmd.PersistentFlags()
+	flags.StringSliceVar(&grafanaSilenceCreateOptions.Matchers, "grafana-silence-matchers", grafanaSilenceCreateOptions.Matchers, "Grafana silence matchers: name=value, name!=value, name=~regex, name!~regex")
+	flags.StringVar(&grafanaSilenceCreateOptions.StartsAt, "grafana-silence-starts-at", grafanaSilenceCreateOptions.StartsAt, "Grafana silence start time, RFC3339 (default now)")
+	flags.StringVar(&grafanaSilenceCreateOptions.Duration, "grafana-silence-duration", grafanaSilenceCreateOptions.Duration, "Grafana silence duration")
+	flags.StringVar(&grafanaSilenceCreateOptions.CreatedBy, "grafana-silence-created-by", grafanaSilenceCreateOptions.CreatedBy, "Grafana silence author")
+	flags.StringVar(&grafanaSilenceCreateOptions.Comment, "grafana-silence-comment", grafanaSilenceCreateOptions.Comment, "Grafana silence comment")
+	silenceCmd.AddCommand(&silenceCreateCmd)
+
+	// tools grafana silence list --grafana-params --grafana-silence-state active
+	silenceListCmd := cobra.Command{
+		Use:   "list",
+		Short: "List silences",
+		Run: func(cmd *cobra.Command, args []string) {
+			stdout.Debug("Grafana listing silences...")
+			common.Debug("Grafana", grafanaSilenceListOptions, stdout)
+
+			bytes, err := grafanaNew(stdout).GetSilences(grafanaSilenceListOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions, grafanaSilenceListOptions}, bytes, stdout)
+		},
+	}
+	flags = silenceListCmd.PersistentFlags()
+	flags.StringSliceVar(&grafanaSilenceListOptions.Matchers, "grafana-silence-matchers", grafanaSilenceListOptions.Matchers, "Grafana silence matchers filter")
+	flags.StringVar(&grafanaSilenceListOptions.State, "grafana-silence-state", grafanaSilenceListOptions.State, "Grafana silence state: active, pending, expired (default all)")
+	silenceCmd.AddCommand(&silenceListCmd)
+
+	// tools grafana silence expire --grafana-params --grafana-silence-id 6f1c...
+	silenceExpireCmd := cobra.Command{
+		Use:   "expire",
+		Short: "Expire silence",
+		Run: func(cmd *cobra.Command, args []string) {
+			stdout.Debug("Grafana expiring silence...")
+			common.Debug("Grafana", grafanaSilenceExpireOptions, stdout)
+
+			bytes, err := grafanaNew(stdout).ExpireSilence(grafanaSilenceExpireOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions, grafanaSilenceExpireOptions}, bytes, stdout)
+		},
+	}
+	flags = silenceExpireCmd.PersistentFlags()
+	flags.StringVar(&grafanaSilenceExpireOptions.ID, "grafana-silence-id", grafanaSilenceExpireOptions.ID, "Grafana silence id")
+	silenceCmd.AddCommand(&silenceExpireCmd)
+
+	alertsCmd := cobra.Command{
+		Use:   "alerts",
+		Short: "Alerts methods",
+	}
+	grafanaCmd.AddCommand(&alertsCmd)
+
+	// tools grafana alerts firing --grafana-params --grafana-alerts-matchers severity=critical
+	alertsFiringCmd := cobra.Command{
+		Use:   "firing",
+		Short: "List firing alerts",
+		Run: func(cmd *cobra.Command, args []string) {
+			stdout.Debug("Grafana getting firing alerts...")
+			common.Debug("Grafana", grafanaAlertsFiringOptions, stdout)
+
+			bytes, err := grafanaNew(stdout).GetFiringAlerts(grafanaAlertsFiringOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions, grafanaAlertsFiringOptions}, bytes, stdout)
+		},
+	}
+	flags = alertsFiringCmd.PersistentFlags()
+	flags.StringSliceVar(&grafanaAlertsFiringOptions.Matchers, "grafana-alerts-matchers", grafanaAlertsFiringOptions.Matchers, "Grafana alerts matchers filter")
+	flags.BoolVar(&grafanaAlertsFiringOptions.Silenced, "grafana-alerts-silenced", grafanaAlertsFiringOptions.Silenced, "Grafana alerts include silenced")
+	flags.BoolVar(&grafanaAlertsFiringOptions.Inhibited, "grafana-alerts-inhibited", grafanaAlertsFiringOptions.Inhibited, "Grafana alerts include inhibited")
+	alertsCmd.AddCommand(&alertsFiringCmd)
+
+	orgsCmd := cobra.Command{
+		Use:   "orgs",
+		Short: "Organization methods, operations run in every org",
+	}
+	flags = orgsCmd.PersistentFlags()
+	flags.StringSliceVar(&grafanaOrgsOptions.Orgs, "grafana-orgs", grafanaOrgsOptions.Orgs, "Grafana org ids or names (all accessible if empty)")
+	grafanaCmd.AddCommand(&orgsCmd)
+
+	// tools grafana orgs list --grafana-params
+	orgsListCmd := cobra.Command{
+		Use:   "list",
+		Short: "List orgs accessible with credentials",
+		Run: func(cmd *cobra.Command, args []string) {
+			stdout.Debug("Grafana listing orgs...")
+			common.Debug("Grafana", grafanaOrgsOptions, stdout)
+
+			bytes, err := grafanaNew(stdout).GetOrgs(grafanaOrgsOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions, grafanaOrgsOptions}, bytes, stdout)
+		},
+	}
+	orgsCmd.AddCommand(&orgsListCmd)
+
+	// tools grafana orgs export --grafana-params --dir ./dashboards, every org goes to its own <dir>/<orgId>
+	orgsExportCmd := cobra.Command{
+		Use:   "export",
+		Short: "Export dashboards and folders of every org",
+		Run: func(cmd *cobra.Command, args []string) {
+			stdout.Debug("Grafana exporting dashboards of orgs...")
+			common.Debug("Grafana", grafanaOrgsOptions, stdout)
+			common.Debug("Grafana", grafanaExportOptions, stdout)
+
+			g := grafanaNew(stdout)
+			bytes, err := g.ForEachOrg(grafanaOrgsOptions, func(org *vendors.GrafanaOrg, orgOptions vendors.GrafanaOptions) ([]byte, error) {
+				exportOptions := grafanaExportOptions
+				if !utils.IsEmpty(exportOptions.Dir) {
+					exportOptions.Dir = filepath.Join(exportOptions.Dir, orgOptions.OrgID)
+				}
+				return g.CustomExportDashboards(orgOptions, exportOptions)
+			})
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions, grafanaOrgsOptions, grafanaExportOptions}, bytes, stdout)
+		},
+	}
+	orgsExportCmd.PersistentFlags().AddFlagSet(exportCmd.PersistentFlags())
+	orgsCmd.AddCommand(&orgsExportCmd)
+
+	// tools grafana orgs get-annotations --grafana-params --grafana-annotation-tags deploy
+	orgsGetAnnotationsCmd := cobra.Command{
+		Use:   "get-annotations",
+		Short: "Get annotations of every org",
+		Run: func(cmd *cobra.Command, args []string) {
+			stdout.Debug("Grafana getting annotations of orgs...")
+			common.Debug("Grafana", grafanaOrgsOptions, stdout)
+			common.Debug("Grafana", grafanaGetAnnotationsOptions, stdout)
+
+			g := grafanaNew(stdout)
+			bytes, err := g.ForEachOrg(grafanaOrgsOptions, func(org *vendors.GrafanaOrg, orgOptions vendors.GrafanaOptions) ([]byte, error) {
+				return g.CustomGetAnnotations(orgOptions, grafanaGetAnnotationsOptions)
+			})
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions, grafanaOrgsOptions, grafanaGetAnnotationsOptions}, bytes, stdout)
+		},
+	}
+	orgsGetAnnotationsCmd.PersistentFlags().AddFlagSet(getAnnotationsCmd.PersistentFlags())
+	orgsCmd.AddCommand(&orgsGetAnnotationsCmd)
+
+	// tools grafana orgs create-annotation --grafana-params --grafana-annotation-text "maintenance" --grafana-annotation-tags maintenance
+	orgsCreateAnnotationCmd := cobra.Command{
+		Use:   "create-annotation",
+		Short: "Create annotation in every org",
+		Run: func(cmd *cobra.Command, args []string) {
+			stdout.Debug("Grafana creating annotation in orgs...")
+			common.Debug("Grafana", grafanaOrgsOptions, stdout)
+			common.Debug("Grafana", grafanaCreateAnnotationOptions, stdout)
+
+			if grafanaCreateAnnotationOptions.Text == "" {
+				stdout.Error("Grafana annotation text is required")
+				return
+			}
+
+			g := grafanaNew(stdout)
+			bytes, err := g.ForEachOrg(grafanaOrgsOptions, func(org *vendors.GrafanaOrg, orgOptions vendors.GrafanaOptions) ([]byte, error) {
+				return g.CustomCreateAnnotation(orgOptions, grafanaCreateAnnotationOptions)
+			})
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(grafanaOutput, "Grafana", []interface{}{grafanaOptions, grafanaOrgsOptions, grafanaCreateAnnotationOptions}, bytes, stdout)
+		},
+	}
+	orgsCreateAnnotationCmd.PersistentFlags().AddFlagSet(createAnnotationCmd.PersistentFlags())
+	orgsCmd.AddCommand(&orgsCreateAnnotationCmd)
+
 	return &grafanaCmd
 }
@@ -1,10 +1,16 @@
 package cmd
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/devopsext/tools/common"
+	"github.com/devopsext/tools/render"
 	"github.com/devopsext/tools/vendors"
 	"github.com/devopsext/utils"
 	"github.com/spf13/cobra"
@@ -17,6 +23,9 @@ var jiraOptions = vendors.JiraOptions{
 	User:        envGet("JIRA_USER", "").(string),
 	Password:    envGet("JIRA_PASSWORD", "").(string),
 	AccessToken: envGet("JIRA_ACCESS_TOKEN", "").(string),
+	Email:       envGet("JIRA_EMAIL", "").(string),
+	APIToken:    envGet("JIRA_API_TOKEN", "").(string),
+	APIVersion:  envGet("JIRA_API_VERSION", 2).(int),
 }
 
 var JiraIssueOptions = vendors.JiraIssueOptions{
@@ -45,11 +54,115 @@ var jiraIssueAddAttachmentOptions = vendors.JiraAddIssueAttachmentOptions{
 var jiraIssueSearchOptions = vendors.JiraSearchIssueOptions{
 	SearchPattern: envGet("JIRA_ISSUE_SEARCH_PATTERN", "").(string),
 	MaxResults:    envGet("JIRA_ISSUE_SEARCH_MAX_RESULTS", 50).(int),
+	Fields:        strings.Split(envGet("JIRA_ISSUE_SEARCH_FIELDS", "").(string), ","),
+	Expand:        strings.Split(envGet("JIRA_ISSUE_SEARCH_EXPAND", "").(string), ","),
+	Limit:         envGet("JIRA_ISSUE_SEARCH_LIMIT", 0).(int),
+	NextPageToken: envGet("JIRA_ISSUE_SEARCH_NEXT_PAGE_TOKEN", false).(bool),
+}
+
+var jiraIssueSearchNDJSON = envGet("JIRA_ISSUE_SEARCH_NDJSON", false).(bool)
+
+var jiraIssueTransitionOptions = vendors.JiraTransitionIssueOptions{
+	To:         envGet("JIRA_ISSUE_TRANSITION_TO", "").(string),
+	Resolution: envGet("JIRA_ISSUE_TRANSITION_RESOLUTION", "").(string),
+	Comment:    envGet("JIRA_ISSUE_TRANSITION_COMMENT", "").(string),
+	Fields:     envGet("JIRA_ISSUE_TRANSITION_FIELDS", "").(string),
+}
+
+var jiraIssueUpsertOptions = vendors.JiraUpsertIssueOptions{
+	Fingerprint:      envGet("JIRA_ISSUE_FINGERPRINT", "").(string),
+	FingerprintField: envGet("JIRA_ISSUE_FINGERPRINT_FIELD", "").(string),
+	Comment:          envGet("JIRA_ISSUE_UPSERT_COMMENT", "").(string),
+	BumpPriority:     envGet("JIRA_ISSUE_UPSERT_BUMP_PRIORITY", false).(bool),
+	ReopenTransition: envGet("JIRA_ISSUE_UPSERT_REOPEN_TRANSITION", "").(string),
+	ReopenWindow:     envGet("JIRA_ISSUE_UPSERT_REOPEN_WINDOW", "7d").(string),
+}
+
+var jiraIssueGetOptions = vendors.JiraGetIssueOptions{
+	Fields: strings.Split(envGet("JIRA_ISSUE_GET_FIELDS", "").(string), ","),
+	Expand: strings.Split(envGet("JIRA_ISSUE_GET_EXPAND", "").(string), ","),
+}
+
+var jiraIssueLinkOptions = vendors.JiraLinkIssueOptions{
+	Type:    envGet("JIRA_ISSUE_LINK_TYPE", "relates to").(string),
+	To:      envGet("JIRA_ISSUE_LINK_TO", "").(string),
+	Comment: envGet("JIRA_ISSUE_LINK_COMMENT", "").(string),
+}
+
+var jiraIssueAddWatcherOptions = vendors.JiraAddIssueWatcherOptions{
+	User: envGet("JIRA_ISSUE_WATCHER", "").(string),
+}
+
+var jiraIssueAddWorklogOptions = vendors.JiraAddIssueWorklogOptions{
+	TimeSpent: envGet("JIRA_ISSUE_WORKLOG_TIME_SPENT", "").(string),
+	Started:   envGet("JIRA_ISSUE_WORKLOG_STARTED", "").(string),
+	Comment:   envGet("JIRA_ISSUE_WORKLOG_COMMENT", "").(string),
+}
+
+var jiraIssueAddRemoteLinkOptions = vendors.JiraAddIssueRemoteLinkOptions{
+	URL:          envGet("JIRA_ISSUE_REMOTE_LINK_URL", "").(string),
+	Title:        envGet("JIRA_ISSUE_REMOTE_LINK_TITLE", "").(string),
+	Summary:      envGet("JIRA_ISSUE_REMOTE_LINK_SUMMARY", "").(string),
+	Icon:         envGet("JIRA_ISSUE_REMOTE_LINK_ICON", "").(string),
+	GlobalID:     envGet("JIRA_ISSUE_REMOTE_LINK_GLOBAL_ID", "").(string),
+	Relationship: envGet("JIRA_ISSUE_REMOTE_LINK_RELATIONSHIP", "").(string),
+}
+
+var jiraIssueBulkCreateOptions = vendors.JiraBulkCreateIssueOptions{
+	Input:     envGet("JIRA_ISSUE_BULK_INPUT", "").(string),
+	Format:    envGet("JIRA_ISSUE_BULK_FORMAT", "").(string),
+	Template:  envGet("JIRA_ISSUE_BULK_TEMPLATE", "").(string),
+	State:     envGet("JIRA_ISSUE_BULK_STATE", "").(string),
+	ChunkSize: envGet("JIRA_ISSUE_BULK_CHUNK_SIZE", 50).(int),
+	IDField:   envGet("JIRA_ISSUE_BULK_ID_FIELD", "").(string),
+}
+
+var jiraServiceDeskRequestOptions = vendors.JiraServiceDeskRequestOptions{
+	ServiceDesk:     envGet("JIRA_SERVICEDESK", "").(string),
+	RequestType:     envGet("JIRA_SERVICEDESK_REQUEST_TYPE", "").(string),
+	Summary:         envGet("JIRA_SERVICEDESK_REQUEST_SUMMARY", "").(string),
+	Description:     envGet("JIRA_SERVICEDESK_REQUEST_DESCRIPTION", "").(string),
+	Fields:          envGet("JIRA_SERVICEDESK_REQUEST_FIELDS", "").(string),
+	Participants:    strings.Split(envGet("JIRA_SERVICEDESK_REQUEST_PARTICIPANTS", "").(string), ","),
+	RaiseOnBehalfOf: envGet("JIRA_SERVICEDESK_REQUEST_ON_BEHALF_OF", "").(string),
+}
+
+var jiraServiceDeskSLAOptions = vendors.JiraServiceDeskSLAOptions{
+	IdOrKey: envGet("JIRA_SERVICEDESK_REQUEST_ID_OR_KEY", "").(string),
 }
 
 var jiraAssetsSearchOptions = vendors.JiraSearchAssetsOptions{
 	SearchPattern: envGet("JIRA_ASSETS_SEARCH_PATTERN", "").(string),
 	ResultPerPage: envGet("JIRA_ASSETS_SEARCH_RESULT_PER_PAGE", 50).(int),
+	Limit:         envGet("JIRA_ASSETS_SEARCH_LIMIT", 0).(int),
+	Flatten:       envGet("JIRA_ASSETS_SEARCH_FLATTEN", false).(bool),
+}
+
+var jiraAssetCreateOptions = vendors.JiraCreateAssetOptions{
+	ObjectSchemeId: envGet("JIRA_ASSET_OBJECT_SCHEMA_ID", "").(string),
+	ObjectTypeId:   envGet("JIRA_ASSET_OBJECT_TYPE_ID", 0).(int),
+	Attributes:     envGet("JIRA_ASSET_ATTRIBUTES", "").(string),
+}
+
+var jiraAssetUpdateOptions = vendors.JiraUpdateAssetOptions{
+	ObjectId:     envGet("JIRA_ASSET_OBJECT_ID", "").(string),
+	ObjectTypeId: envGet("JIRA_ASSET_OBJECT_TYPE_ID", 0).(int),
+	Attributes:   envGet("JIRA_ASSET_ATTRIBUTES", "").(string),
+}
+
+var jiraAssetGetOptions = vendors.JiraGetAssetOptions{
+	ObjectId: envGet("JIRA_ASSET_OBJECT_ID", "").(string),
+}
+
+var jiraAssetDeleteOptions = vendors.JiraDeleteAssetOptions{
+	ObjectId: envGet("JIRA_ASSET_OBJECT_ID", "").(string),
+}
+
+var jiraAssetsSyncOptions = vendors.JiraSyncAssetsOptions{
+	ObjectSchemeId: envGet("JIRA_ASSET_OBJECT_SCHEMA_ID", "").(string),
+	ObjectTypeId:   envGet("JIRA_ASSET_OBJECT_TYPE_ID", 0).(int),
+	KeyAttribute:   envGet("JIRA_ASSETS_SYNC_KEY_ATTRIBUTE", "Name").(string),
+	Objects:        envGet("JIRA_ASSETS_SYNC_OBJECTS", "").(string),
 }
 
 var jiraOutput = common.OutputOptions{
@@ -65,6 +178,56 @@ func jiraNew(stdout *common.Stdout) *vendors.Jira {
 	return vendors.NewJira(jiraOptions)
 }
 
+// stream issues line by line to output file or stdout
+func jiraSearchNDJSON(stdout *common.Stdout) error {
+
+	var w io.Writer = os.Stdout
+	if !utils.IsEmpty(jiraOutput.Output) {
+		f, err := os.Create(jiraOutput.Output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	bw := bufio.NewWriter(w)
+	defer bw.Flush()
+
+	total, err := jiraNew(stdout).SearchIssueFunc(jiraIssueSearchOptions, func(issue json.RawMessage) error {
+		var b bytes.Buffer
+		if err := json.Compact(&b, issue); err != nil {
+			return err
+		}
+		b.WriteByte('\n')
+		_, err := bw.Write(b.Bytes())
+		return err
+	})
+	stdout.Debug("Jira issue search total => %d", total)
+	return err
+}
+
+func jiraBulkCreate(stdout *common.Stdout) ([]byte, error) {
+
+	templateBytes, err := utils.Content(jiraIssueBulkCreateOptions.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := render.NewTextTemplate(render.TemplateOptions{
+		Name:    "jira-bulk",
+		Content: string(templateBytes),
+	}, stdout)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := jiraNew(stdout).BulkCreateIssuesFromInput(jiraIssueBulkCreateOptions, template.RenderObject)
+	if err != nil {
+		return nil, err
+	}
+	return common.JsonMarshal(list)
+}
+
 func NewJiraCommand() *cobra.Command {
 
 	jiraCmd := cobra.Command{
@@ -78,6 +241,9 @@ func NewJiraCommand() *cobra.Command {
 	flags.StringVar(&jiraOptions.User, "jira-user", jiraOptions.User, "Jira user")
 	flags.StringVar(&jiraOptions.Password, "jira-password", jiraOptions.Password, "Jira password")
 	flags.StringVar(&jiraOptions.AccessToken, "jira-access-token", jiraOptions.AccessToken, "Jira Personal Access Token")
+	flags.StringVar(&jiraOptions.Email, "jira-email", jiraOptions.Email, "Jira Cloud user email")
+	flags.StringVar(&jiraOptions.APIToken, "jira-api-token", jiraOptions.APIToken, "Jira Cloud API token")
+	flags.IntVar(&jiraOptions.APIVersion, "jira-api-version", jiraOptions.APIVersion, "Jira REST API version: 2, 3 (Cloud, markdown is converted to ADF, assignee and reporter are account IDs)")
 	flags.StringVar(&jiraOutput.Output, "jira-output", jiraOutput.Output, "Jira output")
 	flags.StringVar(&jiraOutput.Query, "jira-output-query", jiraOutput.Query, "Jira output query")
 
@@ -121,10 +287,54 @@ func NewJiraCommand() *cobra.Command {
 	flags.StringVar(&JiraIssueOptions.ProjectKey, "jira-issue-project-key", JiraIssueOptions.ProjectKey, "Jira issue project key")
 	flags.StringVar(&JiraIssueOptions.Type, "jira-issue-type", JiraIssueOptions.Type, "Jira issue type")
 	flags.StringVar(&JiraIssueOptions.Priority, "jira-issue-priority", JiraIssueOptions.Priority, "Jira issue priority")
-	flags.StringVar(&JiraIssueOptions.Assignee, "jira-issue-assignee", JiraIssueOptions.Assignee, "Jira issue assignee")
-	flags.StringVar(&JiraIssueOptions.Reporter, "jira-issue-reporter", JiraIssueOptions.Reporter, "Jira issue reporter")
+	flags.StringVar(&JiraIssueOptions.Assignee, "jira-issue-assignee", JiraIssueOptions.Assignee, "Jira issue assignee (account ID for API v3)")
+	flags.StringVar(&JiraIssueOptions.Reporter, "jira-issue-reporter", JiraIssueOptions.Reporter, "Jira issue reporter (account ID for API v3)")
 	issueCmd.AddCommand(issueCreateCmd)
 
+	// tools jira issue upsert --jira-params --create-issue-params --upsert-params
+	issueUpsertCmd := &cobra.Command{
+		Use:   "upsert",
+		Short: "Create issue or comment existing one by fingerprint",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Jira upserting issue...")
+			common.Debug("Jira", JiraIssueOptions, stdout)
+			common.Debug("Jira", jiraIssueUpsertOptions, stdout)
+
+			descriptionBytes, err := utils.Content(JiraIssueOptions.Description)
+			if err != nil {
+				stdout.Panic(err)
+			}
+			JiraIssueOptions.Description = string(descriptionBytes)
+
+			commentBytes, err := utils.Content(jiraIssueUpsertOptions.Comment)
+			if err != nil {
+				stdout.Panic(err)
+			}
+			jiraIssueUpsertOptions.Comment = string(commentBytes)
+
+			bytes, err := jiraNew(stdout).UpsertIssue(JiraIssueOptions, jiraIssueUpsertOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(jiraOutput, "Jira", []interface{}{jiraOptions, JiraIssueOptions, jiraIssueUpsertOptions}, bytes, stdout)
+		},
+	}
+	flags = issueUpsertCmd.PersistentFlags()
+	flags.StringVar(&JiraIssueOptions.ProjectKey, "jira-issue-project-key", JiraIssueOptions.ProjectKey, "Jira issue project key")
+	flags.StringVar(&JiraIssueOptions.Type, "jira-issue-type", JiraIssueOptions.Type, "Jira issue type")
+	flags.StringVar(&JiraIssueOptions.Priority, "jira-issue-priority", JiraIssueOptions.Priority, "Jira issue priority")
+	flags.StringVar(&JiraIssueOptions.Assignee, "jira-issue-assignee", JiraIssueOptions.Assignee, "Jira issue assignee (account ID for API v3)")
+	flags.StringVar(&JiraIssueOptions.Reporter, "jira-issue-reporter", JiraIssueOptions.Reporter, "Jira issue reporter (account ID for API v3)")
+	flags.StringVar(&jiraIssueUpsertOptions.Fingerprint, "jira-issue-fingerprint", jiraIssueUpsertOptions.Fingerprint, "Jira issue fingerprint")
+	flags.StringVar(&jiraIssueUpsertOptions.FingerprintField, "jira-issue-fingerprint-field", jiraIssueUpsertOptions.FingerprintField, "Jira issue fingerprint custom field ID (label is used if empty)")
+	flags.StringVar(&jiraIssueUpsertOptions.Comment, "jira-issue-upsert-comment", jiraIssueUpsertOptions.Comment, "Jira issue comment for existing issue (description is used if empty)")
+	flags.BoolVar(&jiraIssueUpsertOptions.BumpPriority, "jira-issue-upsert-bump-priority", jiraIssueUpsertOptions.BumpPriority, "Jira issue raise priority of existing issue if lower")
+	flags.StringVar(&jiraIssueUpsertOptions.ReopenTransition, "jira-issue-upsert-reopen-transition", jiraIssueUpsertOptions.ReopenTransition, "Jira issue transition name or ID to reopen recently closed issue")
+	flags.StringVar(&jiraIssueUpsertOptions.ReopenWindow, "jira-issue-upsert-reopen-window", jiraIssueUpsertOptions.ReopenWindow, "Jira issue reopen window for closed issues (JQL relative duration)")
+	issueCmd.AddCommand(issueUpsertCmd)
+
 	// tools jira issue add-comment --jira-params --issue-params --add-comment-params
 	issueAddCommentCmd := &cobra.Command{
 		Use:   "add-comment",
@@ -237,6 +447,38 @@ func NewJiraCommand() *cobra.Command {
 	flags.StringVar(&JiraIssueOptions.Status, "jira-issue-status", JiraIssueOptions.Status, "Jira issue status")
 	issueCmd.AddCommand(issueChangeTransitionsCmd)
 
+	// tools jira issue transition --jira-params --issue-params --transition-params
+	issueTransitionCmd := &cobra.Command{
+		Use:   "transition",
+		Short: "Transition issue by name or target status",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Jira issue transitioning...")
+			common.Debug("Jira", JiraIssueOptions, stdout)
+			common.Debug("Jira", jiraIssueTransitionOptions, stdout)
+
+			commentBytes, err := utils.Content(jiraIssueTransitionOptions.Comment)
+			if err != nil {
+				stdout.Panic(err)
+			}
+			jiraIssueTransitionOptions.Comment = string(commentBytes)
+
+			bytes, err := jiraNew(stdout).TransitionIssue(JiraIssueOptions, jiraIssueTransitionOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(jiraOutput, "Jira", []interface{}{jiraOptions, JiraIssueOptions, jiraIssueTransitionOptions}, bytes, stdout)
+		},
+	}
+	flags = issueTransitionCmd.PersistentFlags()
+	flags.StringVar(&jiraIssueTransitionOptions.To, "jira-issue-transition-to", jiraIssueTransitionOptions.To, "Jira issue transition name, target status or transition ID")
+	flags.StringVar(&jiraIssueTransitionOptions.To, "to", jiraIssueTransitionOptions.To, "Jira issue transition name, target status or transition ID (short for --jira-issue-transition-to)")
+	flags.StringVar(&jiraIssueTransitionOptions.Resolution, "jira-issue-transition-resolution", jiraIssueTransitionOptions.Resolution, "Jira issue transition resolution")
+	flags.StringVar(&jiraIssueTransitionOptions.Comment, "jira-issue-transition-comment", jiraIssueTransitionOptions.Comment, "Jira issue transition comment")
+	flags.StringVar(&jiraIssueTransitionOptions.Fields, "jira-issue-transition-fields", jiraIssueTransitionOptions.Fields, "Jira issue transition screen fields file")
+	issueCmd.AddCommand(issueTransitionCmd)
+
 	issueSearchCmd := &cobra.Command{
 		Use:   "search",
 		Short: "Search issue",
@@ -251,6 +493,13 @@ func NewJiraCommand() *cobra.Command {
 			}
 			jiraIssueSearchOptions.SearchPattern = string(searchBytes)
 
+			if jiraIssueSearchNDJSON {
+				if err := jiraSearchNDJSON(stdout); err != nil {
+					stdout.Error(err)
+				}
+				return
+			}
+
 			bytes, err := jiraNew(stdout).SearchIssue(jiraIssueSearchOptions)
 			if err != nil {
 				stdout.Error(err)
@@ -261,9 +510,245 @@ func NewJiraCommand() *cobra.Command {
 	}
 	flags = issueSearchCmd.PersistentFlags()
 	flags.StringVar(&jiraIssueSearchOptions.SearchPattern, "jira-issue-search-pattern", jiraIssueSearchOptions.SearchPattern, "Jira issue search pattern")
-	flags.IntVar(&jiraIssueSearchOptions.MaxResults, "jira-issue-search-max-results", jiraIssueSearchOptions.MaxResults, "Jira issue search max results")
+	flags.IntVar(&jiraIssueSearchOptions.MaxResults, "jira-issue-search-max-results", jiraIssueSearchOptions.MaxResults, "Jira issue search max results per page")
+	flags.StringSliceVar(&jiraIssueSearchOptions.Fields, "jira-issue-search-fields", jiraIssueSearchOptions.Fields, "Jira issue search fields")
+	flags.StringSliceVar(&jiraIssueSearchOptions.Fields, "fields", jiraIssueSearchOptions.Fields, "Jira issue search fields (short for --jira-issue-search-fields)")
+	flags.StringSliceVar(&jiraIssueSearchOptions.Expand, "jira-issue-search-expand", jiraIssueSearchOptions.Expand, "Jira issue search expand: changelog, renderedFields, ...")
+	flags.StringSliceVar(&jiraIssueSearchOptions.Expand, "expand", jiraIssueSearchOptions.Expand, "Jira issue search expand (short for --jira-issue-search-expand)")
+	flags.IntVar(&jiraIssueSearchOptions.Limit, "jira-issue-search-limit", jiraIssueSearchOptions.Limit, "Jira issue search hard limit of issues (0 means all)")
+	flags.IntVar(&jiraIssueSearchOptions.Limit, "limit", jiraIssueSearchOptions.Limit, "Jira issue search hard limit (short for --jira-issue-search-limit)")
+	flags.BoolVar(&jiraIssueSearchOptions.NextPageToken, "jira-issue-search-next-page-token", jiraIssueSearchOptions.NextPageToken, "Jira issue search with Jira Cloud nextPageToken API")
+	flags.BoolVar(&jiraIssueSearchNDJSON, "jira-issue-search-ndjson", jiraIssueSearchNDJSON, "Jira issue search streams issues as NDJSON (output query is ignored)")
 	issueCmd.AddCommand((issueSearchCmd))
 
+	// tools jira issue get --jira-params --issue-params --get-params
+	issueGetCmd := &cobra.Command{
+		Use:   "get",
+		Short: "Get issue",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Jira issue getting...")
+			common.Debug("Jira", JiraIssueOptions, stdout)
+			common.Debug("Jira", jiraIssueGetOptions, stdout)
+
+			bytes, err := jiraNew(stdout).GetIssue(JiraIssueOptions, jiraIssueGetOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(jiraOutput, "Jira", []interface{}{jiraOptions, JiraIssueOptions, jiraIssueGetOptions}, bytes, stdout)
+		},
+	}
+	flags = issueGetCmd.PersistentFlags()
+	flags.StringSliceVar(&jiraIssueGetOptions.Fields, "jira-issue-get-fields", jiraIssueGetOptions.Fields, "Jira issue fields")
+	flags.StringSliceVar(&jiraIssueGetOptions.Expand, "jira-issue-get-expand", jiraIssueGetOptions.Expand, "Jira issue expand: changelog, renderedFields, ...")
+	issueCmd.AddCommand(issueGetCmd)
+
+	// tools jira issue link --jira-params --issue-params --link-params
+	issueLinkCmd := &cobra.Command{
+		Use:   "link",
+		Short: "Link issue to another issue",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Jira issue linking...")
+			common.Debug("Jira", JiraIssueOptions, stdout)
+			common.Debug("Jira", jiraIssueLinkOptions, stdout)
+
+			commentBytes, err := utils.Content(jiraIssueLinkOptions.Comment)
+			if err != nil {
+				stdout.Panic(err)
+			}
+			jiraIssueLinkOptions.Comment = string(commentBytes)
+
+			bytes, err := jiraNew(stdout).LinkIssue(JiraIssueOptions, jiraIssueLinkOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(jiraOutput, "Jira", []interface{}{jiraOptions, JiraIssueOptions, jiraIssueLinkOptions}, bytes, stdout)
+		},
+	}
+	flags = issueLinkCmd.PersistentFlags()
+	flags.StringVar(&jiraIssueLinkOptions.Type, "jira-issue-link-type", jiraIssueLinkOptions.Type, "Jira issue link type name, outward or inward description")
+	flags.StringVar(&jiraIssueLinkOptions.Type, "type", jiraIssueLinkOptions.Type, "Jira issue link type (short for --jira-issue-link-type)")
+	flags.StringVar(&jiraIssueLinkOptions.To, "jira-issue-link-to", jiraIssueLinkOptions.To, "Jira issue key to link to")
+	flags.StringVar(&jiraIssueLinkOptions.To, "to", jiraIssueLinkOptions.To, "Jira issue key to link to (short for --jira-issue-link-to)")
+	flags.StringVar(&jiraIssueLinkOptions.Comment, "jira-issue-link-comment", jiraIssueLinkOptions.Comment, "Jira issue link comment")
+	issueCmd.AddCommand(issueLinkCmd)
+
+	// tools jira issue add-watcher --jira-params --issue-params --add-watcher-params
+	issueAddWatcherCmd := &cobra.Command{
+		Use:   "add-watcher",
+		Short: "Issue add watcher",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Jira issue adding watcher...")
+			common.Debug("Jira", JiraIssueOptions, stdout)
+			common.Debug("Jira", jiraIssueAddWatcherOptions, stdout)
+
+			bytes, err := jiraNew(stdout).AddIssueWatcher(JiraIssueOptions, jiraIssueAddWatcherOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(jiraOutput, "Jira", []interface{}{jiraOptions, JiraIssueOptions, jiraIssueAddWatcherOptions}, bytes, stdout)
+		},
+	}
+	flags = issueAddWatcherCmd.PersistentFlags()
+	flags.StringVar(&jiraIssueAddWatcherOptions.User, "jira-issue-watcher", jiraIssueAddWatcherOptions.User, "Jira issue watcher user name (account ID for API v3)")
+	issueCmd.AddCommand(issueAddWatcherCmd)
+
+	// tools jira issue add-worklog --jira-params --issue-params --add-worklog-params
+	issueAddWorklogCmd := &cobra.Command{
+		Use:   "add-worklog",
+		Short: "Issue add worklog",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Jira issue adding worklog...")
+			common.Debug("Jira", JiraIssueOptions, stdout)
+			common.Debug("Jira", jiraIssueAddWorklogOptions, stdout)
+
+			commentBytes, err := utils.Content(jiraIssueAddWorklogOptions.Comment)
+			if err != nil {
+				stdout.Panic(err)
+			}
+			jiraIssueAddWorklogOptions.Comment = string(commentBytes)
+
+			bytes, err := jiraNew(stdout).AddIssueWorklog(JiraIssueOptions, jiraIssueAddWorklogOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(jiraOutput, "Jira", []interface{}{jiraOptions, JiraIssueOptions, jiraIssueAddWorklogOptions}, bytes, stdout)
+		},
+	}
+	flags = issueAddWorklogCmd.PersistentFlags()
+	flags.StringVar(&jiraIssueAddWorklogOptions.TimeSpent, "jira-issue-worklog-time-spent", jiraIssueAddWorklogOptions.TimeSpent, "Jira issue worklog time spent (1h 30m)")
+	flags.StringVar(&jiraIssueAddWorklogOptions.Started, "jira-issue-worklog-started", jiraIssueAddWorklogOptions.Started, "Jira issue worklog start time in RFC3339 (now if empty)")
+	flags.StringVar(&jiraIssueAddWorklogOptions.Comment, "jira-issue-worklog-comment", jiraIssueAddWorklogOptions.Comment, "Jira issue worklog comment")
+	issueCmd.AddCommand(issueAddWorklogCmd)
+
+	// tools jira issue remote-link --jira-params --issue-params --remote-link-params
+	issueRemoteLinkCmd := &cobra.Command{
+		Use:   "remote-link",
+		Short: "Issue add remote link (Grafana, Slack, GitLab, ...)",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Jira issue adding remote link...")
+			common.Debug("Jira", JiraIssueOptions, stdout)
+			common.Debug("Jira", jiraIssueAddRemoteLinkOptions, stdout)
+
+			bytes, err := jiraNew(stdout).AddIssueRemoteLink(JiraIssueOptions, jiraIssueAddRemoteLinkOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(jiraOutput, "Jira", []interface{}{jiraOptions, JiraIssueOptions, jiraIssueAddRemoteLinkOptions}, bytes, stdout)
+		},
+	}
+	flags = issueRemoteLinkCmd.PersistentFlags()
+	flags.StringVar(&jiraIssueAddRemoteLinkOptions.URL, "jira-issue-remote-link-url", jiraIssueAddRemoteLinkOptions.URL, "Jira issue remote link URL")
+	flags.StringVar(&jiraIssueAddRemoteLinkOptions.Title, "jira-issue-remote-link-title", jiraIssueAddRemoteLinkOptions.Title, "Jira issue remote link title (URL if empty)")
+	flags.StringVar(&jiraIssueAddRemoteLinkOptions.Summary, "jira-issue-remote-link-summary", jiraIssueAddRemoteLinkOptions.Summary, "Jira issue remote link summary")
+	flags.StringVar(&jiraIssueAddRemoteLinkOptions.Icon, "jira-issue-remote-link-icon", jiraIssueAddRemoteLinkOptions.Icon, "Jira issue remote link 16x16 icon URL")
+	flags.StringVar(&jiraIssueAddRemoteLinkOptions.GlobalID, "jira-issue-remote-link-global-id", jiraIssueAddRemoteLinkOptions.GlobalID, "Jira issue remote link global ID, existing link is updated (URL if empty)")
+	flags.StringVar(&jiraIssueAddRemoteLinkOptions.Relationship, "jira-issue-remote-link-relationship", jiraIssueAddRemoteLinkOptions.Relationship, "Jira issue remote link relationship")
+	issueCmd.AddCommand(issueRemoteLinkCmd)
+
+	// tools jira issue bulk-create --jira-params --bulk-create-params
+	issueBulkCreateCmd := &cobra.Command{
+		Use:   "bulk-create",
+		Short: "Create issues from CSV or NDJSON records rendered through template",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Jira bulk creating issues...")
+			common.Debug("Jira", jiraIssueBulkCreateOptions, stdout)
+
+			bytes, err := jiraBulkCreate(stdout)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(jiraOutput, "Jira", []interface{}{jiraOptions, jiraIssueBulkCreateOptions}, bytes, stdout)
+		},
+	}
+	flags = issueBulkCreateCmd.PersistentFlags()
+	flags.StringVar(&jiraIssueBulkCreateOptions.Input, "jira-issue-bulk-input", jiraIssueBulkCreateOptions.Input, "Jira issue bulk input file")
+	flags.StringVar(&jiraIssueBulkCreateOptions.Input, "input", jiraIssueBulkCreateOptions.Input, "Jira issue bulk input file (short for --jira-issue-bulk-input)")
+	flags.StringVar(&jiraIssueBulkCreateOptions.Format, "jira-issue-bulk-format", jiraIssueBulkCreateOptions.Format, "Jira issue bulk input format: ndjson, csv (by file extension if empty)")
+	flags.StringVar(&jiraIssueBulkCreateOptions.Template, "jira-issue-bulk-template", jiraIssueBulkCreateOptions.Template, "Jira issue bulk template content or path, renders record into issue fields json")
+	flags.StringVar(&jiraIssueBulkCreateOptions.Template, "template", jiraIssueBulkCreateOptions.Template, "Jira issue bulk template (short for --jira-issue-bulk-template)")
+	flags.StringVar(&jiraIssueBulkCreateOptions.State, "jira-issue-bulk-state", jiraIssueBulkCreateOptions.State, "Jira issue bulk state file to resume after partial failures")
+	flags.StringVar(&jiraIssueBulkCreateOptions.IDField, "jira-issue-bulk-id-field", jiraIssueBulkCreateOptions.IDField, "Jira issue bulk record field or column identifying it in state (record hash if empty)")
+	flags.IntVar(&jiraIssueBulkCreateOptions.ChunkSize, "jira-issue-bulk-chunk-size", jiraIssueBulkCreateOptions.ChunkSize, "Jira issue bulk chunk size (max 50)")
+	issueCmd.AddCommand(issueBulkCreateCmd)
+
+	servicedeskCmd := &cobra.Command{
+		Use:   "servicedesk",
+		Short: "Service desk methods",
+	}
+	jiraCmd.AddCommand(servicedeskCmd)
+
+	servicedeskRequestCmd := &cobra.Command{
+		Use:   "request",
+		Short: "Customer request methods",
+	}
+	servicedeskCmd.AddCommand(servicedeskRequestCmd)
+
+	// tools jira servicedesk request create --jira-params --jira-servicedesk PLAT --jira-servicedesk-request-type "Get IT help" --jira-servicedesk-request-summary ...
+	servicedeskRequestCreateCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create customer request",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Jira service desk request creating...")
+			common.Debug("Jira", jiraServiceDeskRequestOptions, stdout)
+
+			descriptionBytes, err := utils.Content(jiraServiceDeskRequestOptions.Description)
+			if err != nil {
+				stdout.Panic(err)
+			}
+			jiraServiceDeskRequestOptions.Description = string(descriptionBytes)
+
+			bytes, err := jiraNew(stdout).CreateServiceDeskRequest(jiraServiceDeskRequestOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(jiraOutput, "Jira", []interface{}{jiraOptions, jiraServiceDeskRequestOptions}, bytes, stdout)
+		},
+	}
+	flags = servicedeskRequestCreateCmd.PersistentFlags()
+	flags.StringVar(&jiraServiceDeskRequestOptions.ServiceDesk, "jira-servicedesk", jiraServiceDeskRequestOptions.ServiceDesk, "Jira service desk ID, project key or name")
+	flags.StringVar(&jiraServiceDeskRequestOptions.RequestType, "jira-servicedesk-request-type", jiraServiceDeskRequestOptions.RequestType, "Jira service desk request type ID or name")
+	flags.StringVar(&jiraServiceDeskRequestOptions.Summary, "jira-servicedesk-request-summary", jiraServiceDeskRequestOptions.Summary, "Jira service desk request summary")
+	flags.StringVar(&jiraServiceDeskRequestOptions.Description, "jira-servicedesk-request-description", jiraServiceDeskRequestOptions.Description, "Jira service desk request description content or path")
+	flags.StringVar(&jiraServiceDeskRequestOptions.Fields, "jira-servicedesk-request-fields", jiraServiceDeskRequestOptions.Fields, "Jira service desk request field values json, content or path")
+	flags.StringSliceVar(&jiraServiceDeskRequestOptions.Participants, "jira-servicedesk-request-participants", jiraServiceDeskRequestOptions.Participants, "Jira service desk request participants (usernames or account IDs on Cloud)")
+	flags.StringVar(&jiraServiceDeskRequestOptions.RaiseOnBehalfOf, "jira-servicedesk-request-on-behalf-of", jiraServiceDeskRequestOptions.RaiseOnBehalfOf, "Jira service desk request customer to raise on behalf of")
+	servicedeskRequestCmd.AddCommand(servicedeskRequestCreateCmd)
+
+	// tools jira servicedesk request sla --jira-params --jira-servicedesk-request-id-or-key PLAT-1
+	servicedeskRequestSLACmd := &cobra.Command{
+		Use:   "sla",
+		Short: "Get customer request SLA",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Jira service desk request getting SLA...")
+			common.Debug("Jira", jiraServiceDeskSLAOptions, stdout)
+
+			bytes, err := jiraNew(stdout).GetServiceDeskRequestSLA(jiraServiceDeskSLAOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(jiraOutput, "Jira", []interface{}{jiraOptions, jiraServiceDeskSLAOptions}, bytes, stdout)
+		},
+	}
+	flags = servicedeskRequestSLACmd.PersistentFlags()
+	flags.StringVar(&jiraServiceDeskSLAOptions.IdOrKey, "jira-servicedesk-request-id-or-key", jiraServiceDeskSLAOptions.IdOrKey, "Jira service desk request ID or key")
+	servicedeskRequestCmd.AddCommand(servicedeskRequestSLACmd)
+
 	assetsCmd := &cobra.Command{
 		Use:   "assets",
 		Short: "Assets methods",
@@ -271,6 +756,8 @@ func NewJiraCommand() *cobra.Command {
 	flags = assetsCmd.PersistentFlags()
 	flags.StringVar(&jiraAssetsSearchOptions.SearchPattern, "jira-assets-search-pattern", jiraAssetsSearchOptions.SearchPattern, "Jira assets search pattern")
 	flags.IntVar(&jiraAssetsSearchOptions.ResultPerPage, "jira-assets-search-results-per-page", jiraAssetsSearchOptions.ResultPerPage, "Jira assets result per page")
+	flags.IntVar(&jiraAssetsSearchOptions.Limit, "jira-assets-search-limit", jiraAssetsSearchOptions.Limit, "Jira assets max objects to return (all if empty)")
+	flags.BoolVar(&jiraAssetsSearchOptions.Flatten, "jira-assets-search-flatten", jiraAssetsSearchOptions.Flatten, "Jira assets objects as attribute name to value maps")
 	jiraCmd.AddCommand(assetsCmd)
 
 	assetsSearchCmd := &cobra.Command{
@@ -296,5 +783,112 @@ func NewJiraCommand() *cobra.Command {
 	}
 	assetsCmd.AddCommand(assetsSearchCmd)
 
+	// tools jira assets create --jira-params --jira-asset-object-type-id 12 --jira-asset-attributes '{"Name":"api","Tier":"1"}'
+	assetsCreateCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create asset",
+		Run: func(cmd *cobra.Command, args []string) {
+			stdout.Debug("Jira asset creating...")
+			common.Debug("Jira", jiraAssetCreateOptions, stdout)
+
+			bytes, err := jiraNew(stdout).CreateAsset(jiraAssetCreateOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(jiraOutput, "Jira", []interface{}{jiraOptions, jiraAssetCreateOptions}, bytes, stdout)
+		},
+	}
+	flags = assetsCreateCmd.PersistentFlags()
+	flags.StringVar(&jiraAssetCreateOptions.ObjectSchemeId, "jira-asset-object-schema-id", jiraAssetCreateOptions.ObjectSchemeId, "Jira asset object schema id")
+	flags.IntVar(&jiraAssetCreateOptions.ObjectTypeId, "jira-asset-object-type-id", jiraAssetCreateOptions.ObjectTypeId, "Jira asset object type id")
+	flags.StringVar(&jiraAssetCreateOptions.Attributes, "jira-asset-attributes", jiraAssetCreateOptions.Attributes, "Jira asset attributes json map of name or id to value, content or path")
+	assetsCmd.AddCommand(assetsCreateCmd)
+
+	// tools jira assets update --jira-params --jira-asset-object-id 345 --jira-asset-attributes '{"Tier":"2"}'
+	assetsUpdateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update asset",
+		Run: func(cmd *cobra.Command, args []string) {
+			stdout.Debug("Jira asset updating...")
+			common.Debug("Jira", jiraAssetUpdateOptions, stdout)
+
+			bytes, err := jiraNew(stdout).UpdateAsset(jiraAssetUpdateOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(jiraOutput, "Jira", []interface{}{jiraOptions, jiraAssetUpdateOptions}, bytes, stdout)
+		},
+	}
+	flags = assetsUpdateCmd.PersistentFlags()
+	flags.StringVar(&jiraAssetUpdateOptions.ObjectId, "jira-asset-object-id", jiraAssetUpdateOptions.ObjectId, "Jira asset object id")
+	flags.IntVar(&jiraAssetUpdateOptions.ObjectTypeId, "jira-asset-object-type-id", jiraAssetUpdateOptions.ObjectTypeId, "Jira asset object type id (taken from object if empty)")
+	flags.StringVar(&jiraAssetUpdateOptions.Attributes, "jira-asset-attributes", jiraAssetUpdateOptions.Attributes, "Jira asset attributes json map of name or id to value, content or path")
+	assetsCmd.AddCommand(assetsUpdateCmd)
+
+	// tools jira assets get --jira-params --jira-asset-object-id 345
+	assetsGetCmd := &cobra.Command{
+		Use:   "get",
+		Short: "Get asset",
+		Run: func(cmd *cobra.Command, args []string) {
+			stdout.Debug("Jira asset getting...")
+			common.Debug("Jira", jiraAssetGetOptions, stdout)
+
+			bytes, err := jiraNew(stdout).GetAsset(jiraAssetGetOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(jiraOutput, "Jira", []interface{}{jiraOptions, jiraAssetGetOptions}, bytes, stdout)
+		},
+	}
+	flags = assetsGetCmd.PersistentFlags()
+	flags.StringVar(&jiraAssetGetOptions.ObjectId, "jira-asset-object-id", jiraAssetGetOptions.ObjectId, "Jira asset object id")
+	assetsCmd.AddCommand(assetsGetCmd)
+
+	// tools jira assets delete --jira-params --jira-asset-object-id 345
+	assetsDeleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete asset",
+		Run: func(cmd *cobra.Command, args []string) {
+			stdout.Debug("Jira asset deleting...")
+			common.Debug("Jira", jiraAssetDeleteOptions, stdout)
+
+			bytes, err := jiraNew(stdout).DeleteAsset(jiraAssetDeleteOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(jiraOutput, "Jira", []interface{}{jiraOptions, jiraAssetDeleteOptions}, bytes, stdout)
+		},
+	}
+	flags = assetsDeleteCmd.PersistentFlags()
+	flags.StringVar(&jiraAssetDeleteOptions.ObjectId, "jira-asset-object-id", jiraAssetDeleteOptions.ObjectId, "Jira asset object id")
+	assetsCmd.AddCommand(assetsDeleteCmd)
+
+	// tools jira assets sync --jira-params --jira-asset-object-type-id 12 --jira-assets-sync-key-attribute Name --jira-assets-sync-objects objects.json
+	assetsSyncCmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Sync assets matching them by key attribute",
+		Run: func(cmd *cobra.Command, args []string) {
+			stdout.Debug("Jira assets syncing...")
+			common.Debug("Jira", jiraAssetsSyncOptions, stdout)
+
+			bytes, err := jiraNew(stdout).SyncAssets(jiraAssetsSyncOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(jiraOutput, "Jira", []interface{}{jiraOptions, jiraAssetsSyncOptions}, bytes, stdout)
+		},
+	}
+	flags = assetsSyncCmd.PersistentFlags()
+	flags.StringVar(&jiraAssetsSyncOptions.ObjectSchemeId, "jira-asset-object-schema-id", jiraAssetsSyncOptions.ObjectSchemeId, "Jira asset object schema id")
+	flags.IntVar(&jiraAssetsSyncOptions.ObjectTypeId, "jira-asset-object-type-id", jiraAssetsSyncOptions.ObjectTypeId, "Jira asset object type id")
+	flags.StringVar(&jiraAssetsSyncOptions.KeyAttribute, "jira-assets-sync-key-attribute", jiraAssetsSyncOptions.KeyAttribute, "Jira assets sync key attribute to match objects")
+	flags.StringVar(&jiraAssetsSyncOptions.Objects, "jira-assets-sync-objects", jiraAssetsSyncOptions.Objects, "Jira assets sync objects json list of attribute maps, content or path")
+	assetsCmd.AddCommand(assetsSyncCmd)
+
 	return &jiraCmd
 }
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/devopsext/tools/common"
+	"github.com/devopsext/tools/vendors"
+	"github.com/devopsext/utils"
+	"github.com/spf13/cobra"
+)
+
+var mattermostOptions = vendors.MattermostOptions{
+	Timeout:    envGet("MATTERMOST_TIMEOUT", 30).(int),
+	Insecure:   envGet("MATTERMOST_INSECURE", false).(bool),
+	URL:        envGet("MATTERMOST_URL", "").(string),
+	Token:      envGet("MATTERMOST_TOKEN", "").(string),
+	Channel:    envGet("MATTERMOST_CHANNEL", "").(string),
+	Title:      envGet("MATTERMOST_TITLE", "").(string),
+	Message:    envGet("MATTERMOST_MESSAGE", "").(string),
+	ImageURL:   envGet("MATTERMOST_IMAGE_URL", "").(string),
+	FileName:   envGet("MATTERMOST_FILENAME", "").(string),
+	File:       envGet("MATTERMOST_FILE", "").(string),
+	ParentID:   envGet("MATTERMOST_THREAD", "").(string),
+	QuoteColor: envGet("MATTERMOST_QUOTE_COLOR", "").(string),
+}
+
+var mattermostReactionOptions = vendors.MattermostReactionOptions{
+	Name: envGet("MATTERMOST_REACTION_NAME", "").(string),
+}
+
+var mattermostOutput = common.OutputOptions{
+	Output: envGet("MATTERMOST_OUTPUT", "").(string),
+	Query:  envGet("MATTERMOST_OUTPUT_QUERY", "").(string),
+}
+
+func mattermostNew(stdout *common.Stdout) *vendors.Mattermost {
+
+	common.Debug("Mattermost", mattermostOptions, stdout)
+	common.Debug("Mattermost", mattermostOutput, stdout)
+
+	messageBytes, err := utils.Content(mattermostOptions.Message)
+	if err != nil {
+		stdout.Panic(err)
+	}
+	mattermostOptions.Message = string(messageBytes)
+
+	if utils.IsEmpty(mattermostOptions.FileName) && utils.FileExists(mattermostOptions.File) {
+		mattermostOptions.FileName = filepath.Base(mattermostOptions.File)
+	}
+
+	fileBytes, err := utils.Content(mattermostOptions.File)
+	if err != nil {
+		stdout.Panic(err)
+	}
+	mattermostOptions.File = string(fileBytes)
+
+	return vendors.NewMattermost(mattermostOptions)
+}
+
+func NewMattermostCommand() *cobra.Command {
+
+	mattermostCmd := &cobra.Command{
+		Use:   "mattermost",
+		Short: "Mattermost tools",
+	}
+
+	flags := mattermostCmd.PersistentFlags()
+	flags.IntVar(&mattermostOptions.Timeout, "mattermost-timeout", mattermostOptions.Timeout, "Mattermost timeout")
+	flags.BoolVar(&mattermostOptions.Insecure, "mattermost-insecure", mattermostOptions.Insecure, "Mattermost insecure")
+	flags.StringVar(&mattermostOptions.URL, "mattermost-url", mattermostOptions.URL, "Mattermost URL")
+	flags.StringVar(&mattermostOptions.Token, "mattermost-token", mattermostOptions.Token, "Mattermost personal access token")
+	flags.StringVar(&mattermostOptions.Channel, "mattermost-channel", mattermostOptions.Channel, "Mattermost channel ID")
+	flags.StringVar(&mattermostOptions.Title, "mattermost-title", mattermostOptions.Title, "Mattermost title")
+	flags.StringVar(&mattermostOptions.Message, "mattermost-message", mattermostOptions.Message, "Mattermost message")
+	flags.StringVar(&mattermostOptions.ImageURL, "mattermost-image-url", mattermostOptions.ImageURL, "Mattermost image url")
+	flags.StringVar(&mattermostOptions.FileName, "mattermost-filename", mattermostOptions.FileName, "Mattermost file name")
+	flags.StringVar(&mattermostOptions.File, "mattermost-file", mattermostOptions.File, "Mattermost file content or path")
+	flags.StringVar(&mattermostOptions.ParentID, "mattermost-thread", mattermostOptions.ParentID, "Mattermost thread (root post ID)")
+	flags.StringVar(&mattermostOptions.QuoteColor, "mattermost-quote-color", mattermostOptions.QuoteColor, "Mattermost quote color in hex format (#008000, no quote by default)")
+	flags.StringVar(&mattermostOutput.Output, "mattermost-output", mattermostOutput.Output, "Mattermost output")
+	flags.StringVar(&mattermostOutput.Query, "mattermost-output-query", mattermostOutput.Query, "Mattermost output query")
+
+	mattermostCmd.AddCommand(&cobra.Command{
+		Use:   "send-message",
+		Short: "Send text message",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Mattermost sending message...")
+			bytes, err := mattermostNew(stdout).SendMessage()
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(mattermostOutput, "Mattermost", []interface{}{mattermostOptions}, bytes, stdout)
+		},
+	})
+
+	mattermostCmd.AddCommand(&cobra.Command{
+		Use:   "send-file",
+		Short: "Send file",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Mattermost sending file...")
+			bytes, err := mattermostNew(stdout).SendFile()
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(mattermostOutput, "Mattermost", []interface{}{mattermostOptions}, bytes, stdout)
+		},
+	})
+
+	addReactionCmd := &cobra.Command{
+		Use:   "add-reaction",
+		Short: "Add reaction",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Mattermost add reaction...")
+			common.Debug("Mattermost", mattermostReactionOptions, stdout)
+
+			bytes, err := mattermostNew(stdout).AddReaction(mattermostReactionOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(mattermostOutput, "Mattermost", []interface{}{mattermostOptions, mattermostReactionOptions}, bytes, stdout)
+		},
+	}
+	flags = addReactionCmd.PersistentFlags()
+	flags.StringVar(&mattermostReactionOptions.Name, "mattermost-reaction-name", mattermostReactionOptions.Name, "Mattermost reaction name")
+	mattermostCmd.AddCommand(addReactionCmd)
+
+	return mattermostCmd
+}
@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/devopsext/tools/common"
+	"github.com/devopsext/tools/vendors"
+	"github.com/devopsext/utils"
+	"github.com/spf13/cobra"
+)
+
+var opsgenieOptions = vendors.OpsgenieOptions{
+	Timeout:  envGet("OPSGENIE_TIMEOUT", 30).(int),
+	Insecure: envGet("OPSGENIE_INSECURE", false).(bool),
+	URL:      envGet("OPSGENIE_URL", "https://api.opsgenie.com").(string),
+	Token:    envGet("OPSGENIE_TOKEN", "").(string),
+}
+
+var opsgenieAlertOptions = vendors.OpsgenieAlertOptions{
+	Title:      envGet("OPSGENIE_ALERT_TITLE", "").(string),
+	Body:       envGet("OPSGENIE_ALERT_BODY", "").(string),
+	Priority:   envGet("OPSGENIE_ALERT_PRIORITY", "").(string),
+	Responders: strings.Split(envGet("OPSGENIE_ALERT_RESPONDERS", "").(string), ","),
+	Alias:      envGet("OPSGENIE_ALERT_ALIAS", "").(string),
+	Tags:       strings.Split(envGet("OPSGENIE_ALERT_TAGS", "").(string), ","),
+	Source:     envGet("OPSGENIE_ALERT_SOURCE", "").(string),
+	Entity:     envGet("OPSGENIE_ALERT_ENTITY", "").(string),
+}
+
+var opsgenieAlertActionOptions = vendors.OpsgenieAlertActionOptions{
+	ID:     envGet("OPSGENIE_ALERT_ID", "").(string),
+	User:   envGet("OPSGENIE_ALERT_USER", "").(string),
+	Source: envGet("OPSGENIE_ALERT_SOURCE", "").(string),
+	Note:   envGet("OPSGENIE_ALERT_NOTE", "").(string),
+}
+
+var opsgenieGetAlertsOptions = vendors.OpsgenieGetAlertsOptions{
+	Query: envGet("OPSGENIE_ALERTS_QUERY", "").(string),
+	Limit: envGet("OPSGENIE_ALERTS_LIMIT", 10).(int),
+}
+
+var opsgenieOnCallOptions = vendors.OpsgenieOnCallOptions{
+	Schedule: envGet("OPSGENIE_ONCALL_SCHEDULE", "").(string),
+	Date:     envGet("OPSGENIE_ONCALL_DATE", "").(string),
+	Flat:     envGet("OPSGENIE_ONCALL_FLAT", true).(bool),
+}
+
+var opsgenieOutput = common.OutputOptions{
+	Output: envGet("OPSGENIE_OUTPUT", "").(string),
+	Query:  envGet("OPSGENIE_OUTPUT_QUERY", "").(string),
+}
+
+func opsgenieNew(stdout *common.Stdout) *vendors.Opsgenie {
+
+	common.Debug("Opsgenie", opsgenieOptions, stdout)
+	common.Debug("Opsgenie", opsgenieOutput, stdout)
+
+	opsgenie := vendors.NewOpsgenie(opsgenieOptions, stdout)
+	if opsgenie == nil {
+		stdout.Panic("No Opsgenie")
+	}
+	return opsgenie
+}
+
+func NewOpsgenieCommand() *cobra.Command {
+
+	opsgenieCmd := &cobra.Command{
+		Use:   "opsgenie",
+		Short: "Opsgenie tools",
+	}
+	flags := opsgenieCmd.PersistentFlags()
+	flags.IntVar(&opsgenieOptions.Timeout, "opsgenie-timeout", opsgenieOptions.Timeout, "Opsgenie timeout in seconds")
+	flags.BoolVar(&opsgenieOptions.Insecure, "opsgenie-insecure", opsgenieOptions.Insecure, "Opsgenie insecure")
+	flags.StringVar(&opsgenieOptions.URL, "opsgenie-url", opsgenieOptions.URL, "Opsgenie URL")
+	flags.StringVar(&opsgenieOptions.Token, "opsgenie-token", opsgenieOptions.Token, "Opsgenie API key")
+	flags.StringVar(&opsgenieOutput.Output, "opsgenie-output", opsgenieOutput.Output, "Opsgenie output")
+	flags.StringVar(&opsgenieOutput.Query, "opsgenie-output-query", opsgenieOutput.Query, "Opsgenie output query")
+
+	alertCmd := &cobra.Command{
+		Use:   "alert",
+		Short: "Alert methods",
+	}
+	flags = alertCmd.PersistentFlags()
+	flags.StringVar(&opsgenieAlertOptions.Alias, "opsgenie-alert-alias", opsgenieAlertOptions.Alias, "Opsgenie alert alias (used for deduplication)")
+	opsgenieCmd.AddCommand(alertCmd)
+
+	// tools opsgenie alert create --alert-params
+	createAlertCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create alert",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Opsgenie creating alert...")
+			common.Debug("Opsgenie", opsgenieAlertOptions, stdout)
+
+			bodyBytes, err := utils.Content(opsgenieAlertOptions.Body)
+			if err != nil {
+				stdout.Panic(err)
+			}
+			opsgenieAlertOptions.Body = string(bodyBytes)
+
+			bytes, err := opsgenieNew(stdout).CreateAlert(opsgenieAlertOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(opsgenieOutput, "Opsgenie", []interface{}{opsgenieOptions, opsgenieAlertOptions}, bytes, stdout)
+		},
+	}
+	flags = createAlertCmd.PersistentFlags()
+	flags.StringVar(&opsgenieAlertOptions.Title, "opsgenie-alert-title", opsgenieAlertOptions.Title, "Opsgenie alert title")
+	flags.StringVar(&opsgenieAlertOptions.Body, "opsgenie-alert-body", opsgenieAlertOptions.Body, "Opsgenie alert body")
+	flags.StringVar(&opsgenieAlertOptions.Priority, "opsgenie-alert-priority", opsgenieAlertOptions.Priority, "Opsgenie alert priority (P1-P5)")
+	flags.StringSliceVar(&opsgenieAlertOptions.Responders, "opsgenie-alert-responders", opsgenieAlertOptions.Responders, "Opsgenie alert responders (team:name, user:username, escalation:name, schedule:name)")
+	flags.StringSliceVar(&opsgenieAlertOptions.Tags, "opsgenie-alert-tags", opsgenieAlertOptions.Tags, "Opsgenie alert tags")
+	flags.StringVar(&opsgenieAlertOptions.Source, "opsgenie-alert-source", opsgenieAlertOptions.Source, "Opsgenie alert source")
+	flags.StringVar(&opsgenieAlertOptions.Entity, "opsgenie-alert-entity", opsgenieAlertOptions.Entity, "Opsgenie alert entity")
+	alertCmd.AddCommand(createAlertCmd)
+
+	alertActionFlags := func(cmd *cobra.Command) {
+		flags := cmd.PersistentFlags()
+		flags.StringVar(&opsgenieAlertActionOptions.ID, "opsgenie-alert-id", opsgenieAlertActionOptions.ID, "Opsgenie alert ID (alias is used if empty)")
+		flags.StringVar(&opsgenieAlertActionOptions.User, "opsgenie-alert-user", opsgenieAlertActionOptions.User, "Opsgenie alert action user")
+		flags.StringVar(&opsgenieAlertActionOptions.Source, "opsgenie-alert-source", opsgenieAlertActionOptions.Source, "Opsgenie alert action source")
+		flags.StringVar(&opsgenieAlertActionOptions.Note, "opsgenie-alert-note", opsgenieAlertActionOptions.Note, "Opsgenie alert note content or path")
+	}
+
+	// tools opsgenie alert close --alert-params --action-params
+	closeAlertCmd := &cobra.Command{
+		Use:   "close",
+		Short: "Close alert",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Opsgenie closing alert...")
+			common.Debug("Opsgenie", opsgenieAlertActionOptions, stdout)
+
+			noteBytes, err := utils.Content(opsgenieAlertActionOptions.Note)
+			if err != nil {
+				stdout.Panic(err)
+			}
+			opsgenieAlertActionOptions.Note = string(noteBytes)
+
+			bytes, err := opsgenieNew(stdout).CloseAlert(opsgenieAlertOptions, opsgenieAlertActionOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(opsgenieOutput, "Opsgenie", []interface{}{opsgenieOptions, opsgenieAlertOptions, opsgenieAlertActionOptions}, bytes, stdout)
+		},
+	}
+	alertActionFlags(closeAlertCmd)
+	alertCmd.AddCommand(closeAlertCmd)
+
+	// tools opsgenie alert ack --alert-params --action-params
+	ackAlertCmd := &cobra.Command{
+		Use:   "ack",
+		Short: "Acknowledge alert",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Opsgenie acknowledging alert...")
+			common.Debug("Opsgenie", opsgenieAlertActionOptions, stdout)
+
+			noteBytes, err := utils.Content(opsgenieAlertActionOptions.Note)
+			if err != nil {
+				stdout.Panic(err)
+			}
+			opsgenieAlertActionOptions.Note = string(noteBytes)
+
+			bytes, err := opsgenieNew(stdout).AckAlert(opsgenieAlertOptions, opsgenieAlertActionOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(opsgenieOutput, "Opsgenie", []interface{}{opsgenieOptions, opsgenieAlertOptions, opsgenieAlertActionOptions}, bytes, stdout)
+		},
+	}
+	alertActionFlags(ackAlertCmd)
+	alertCmd.AddCommand(ackAlertCmd)
+
+	// tools opsgenie alert add-note --alert-params --action-params
+	addNoteCmd := &cobra.Command{
+		Use:   "add-note",
+		Short: "Add alert note",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Opsgenie adding alert note...")
+			common.Debug("Opsgenie", opsgenieAlertActionOptions, stdout)
+
+			noteBytes, err := utils.Content(opsgenieAlertActionOptions.Note)
+			if err != nil {
+				stdout.Panic(err)
+			}
+			opsgenieAlertActionOptions.Note = string(noteBytes)
+
+			bytes, err := opsgenieNew(stdout).AddAlertNote(opsgenieAlertOptions, opsgenieAlertActionOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(opsgenieOutput, "Opsgenie", []interface{}{opsgenieOptions, opsgenieAlertOptions, opsgenieAlertActionOptions}, bytes, stdout)
+		},
+	}
+	alertActionFlags(addNoteCmd)
+	alertCmd.AddCommand(addNoteCmd)
+
+	// tools opsgenie alert list --list-params
+	listAlertsCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List alerts",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Opsgenie getting alerts...")
+			common.Debug("Opsgenie", opsgenieGetAlertsOptions, stdout)
+
+			bytes, err := opsgenieNew(stdout).GetAlerts(opsgenieGetAlertsOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(opsgenieOutput, "Opsgenie", []interface{}{opsgenieOptions, opsgenieGetAlertsOptions}, bytes, stdout)
+		},
+	}
+	flags = listAlertsCmd.PersistentFlags()
+	flags.StringVar(&opsgenieGetAlertsOptions.Query, "opsgenie-alerts-query", opsgenieGetAlertsOptions.Query, "Opsgenie alerts search query")
+	flags.IntVar(&opsgenieGetAlertsOptions.Limit, "opsgenie-alerts-limit", opsgenieGetAlertsOptions.Limit, "Opsgenie alerts limit")
+	alertCmd.AddCommand(listAlertsCmd)
+
+	onCallCmd := &cobra.Command{
+		Use:   "oncall",
+		Short: "On-call methods",
+	}
+	opsgenieCmd.AddCommand(onCallCmd)
+
+	// tools opsgenie oncall who --oncall-params
+	whoCmd := &cobra.Command{
+		Use:   "who",
+		Short: "Get on-call participants for schedule",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Opsgenie getting on-calls...")
+			common.Debug("Opsgenie", opsgenieOnCallOptions, stdout)
+
+			bytes, err := opsgenieNew(stdout).GetOnCalls(opsgenieOnCallOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(opsgenieOutput, "Opsgenie", []interface{}{opsgenieOptions, opsgenieOnCallOptions}, bytes, stdout)
+		},
+	}
+	flags = whoCmd.PersistentFlags()
+	flags.StringVar(&opsgenieOnCallOptions.Schedule, "opsgenie-oncall-schedule", opsgenieOnCallOptions.Schedule, "Opsgenie schedule name")
+	flags.StringVar(&opsgenieOnCallOptions.Date, "opsgenie-oncall-date", opsgenieOnCallOptions.Date, "Opsgenie on-call date (RFC3339, now if empty)")
+	flags.BoolVar(&opsgenieOnCallOptions.Flat, "opsgenie-oncall-flat", opsgenieOnCallOptions.Flat, "Opsgenie on-call flat participants list")
+	onCallCmd.AddCommand(whoCmd)
+
+	return opsgenieCmd
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/devopsext/tools/common"
+	"github.com/devopsext/tools/vendors"
+	"github.com/devopsext/utils"
+	"github.com/spf13/cobra"
+)
+
+var rocketChatOptions = vendors.RocketChatOptions{
+	Timeout:    envGet("ROCKETCHAT_TIMEOUT", 30).(int),
+	Insecure:   envGet("ROCKETCHAT_INSECURE", false).(bool),
+	URL:        envGet("ROCKETCHAT_URL", "").(string),
+	UserID:     envGet("ROCKETCHAT_USER_ID", "").(string),
+	Token:      envGet("ROCKETCHAT_TOKEN", "").(string),
+	Channel:    envGet("ROCKETCHAT_CHANNEL", "").(string),
+	Title:      envGet("ROCKETCHAT_TITLE", "").(string),
+	Message:    envGet("ROCKETCHAT_MESSAGE", "").(string),
+	ImageURL:   envGet("ROCKETCHAT_IMAGE_URL", "").(string),
+	FileName:   envGet("ROCKETCHAT_FILENAME", "").(string),
+	File:       envGet("ROCKETCHAT_FILE", "").(string),
+	ParentID:   envGet("ROCKETCHAT_THREAD", "").(string),
+	QuoteColor: envGet("ROCKETCHAT_QUOTE_COLOR", "").(string),
+}
+
+var rocketChatReactionOptions = vendors.RocketChatReactionOptions{
+	Name: envGet("ROCKETCHAT_REACTION_NAME", "").(string),
+}
+
+var rocketChatOutput = common.OutputOptions{
+	Output: envGet("ROCKETCHAT_OUTPUT", "").(string),
+	Query:  envGet("ROCKETCHAT_OUTPUT_QUERY", "").(string),
+}
+
+func rocketChatNew(stdout *common.Stdout) *vendors.RocketChat {
+
+	common.Debug("RocketChat", rocketChatOptions, stdout)
+	common.Debug("RocketChat", rocketChatOutput, stdout)
+
+	messageBytes, err := utils.Content(rocketChatOptions.Message)
+	if err != nil {
+		stdout.Panic(err)
+	}
+	rocketChatOptions.Message = string(messageBytes)
+
+	if utils.IsEmpty(rocketChatOptions.FileName) && utils.FileExists(rocketChatOptions.File) {
+		rocketChatOptions.FileName = filepath.Base(rocketChatOptions.File)
+	}
+
+	fileBytes, err := utils.Content(rocketChatOptions.File)
+	if err != nil {
+		stdout.Panic(err)
+	}
+	rocketChatOptions.File = string(fileBytes)
+
+	return vendors.NewRocketChat(rocketChatOptions)
+}
+
+func NewRocketChatCommand() *cobra.Command {
+
+	rocketChatCmd := &cobra.Command{
+		Use:   "rocketchat",
+		Short: "Rocket.Chat tools",
+	}
+
+	flags := rocketChatCmd.PersistentFlags()
+	flags.IntVar(&rocketChatOptions.Timeout, "rocketchat-timeout", rocketChatOptions.Timeout, "RocketChat timeout")
+	flags.BoolVar(&rocketChatOptions.Insecure, "rocketchat-insecure", rocketChatOptions.Insecure, "RocketChat insecure")
+	flags.StringVar(&rocketChatOptions.URL, "rocketchat-url", rocketChatOptions.URL, "RocketChat URL")
+	flags.StringVar(&rocketChatOptions.UserID, "rocketchat-user-id", rocketChatOptions.UserID, "RocketChat user ID")
+	flags.StringVar(&rocketChatOptions.Token, "rocketchat-token", rocketChatOptions.Token, "RocketChat personal access token")
+	flags.StringVar(&rocketChatOptions.Channel, "rocketchat-channel", rocketChatOptions.Channel, "RocketChat channel (#channel, @user or room ID)")
+	flags.StringVar(&rocketChatOptions.Title, "rocketchat-title", rocketChatOptions.Title, "RocketChat title")
+	flags.StringVar(&rocketChatOptions.Message, "rocketchat-message", rocketChatOptions.Message, "RocketChat message")
+	flags.StringVar(&rocketChatOptions.ImageURL, "rocketchat-image-url", rocketChatOptions.ImageURL, "RocketChat image url")
+	flags.StringVar(&rocketChatOptions.FileName, "rocketchat-filename", rocketChatOptions.FileName, "RocketChat file name")
+	flags.StringVar(&rocketChatOptions.File, "rocketchat-file", rocketChatOptions.File, "RocketChat file content or path")
+	flags.StringVar(&rocketChatOptions.ParentID, "rocketchat-thread", rocketChatOptions.ParentID, "RocketChat thread (parent message ID)")
+	flags.StringVar(&rocketChatOptions.QuoteColor, "rocketchat-quote-color", rocketChatOptions.QuoteColor, "RocketChat quote color in hex format (#008000, no quote by default)")
+	flags.StringVar(&rocketChatOutput.Output, "rocketchat-output", rocketChatOutput.Output, "RocketChat output")
+	flags.StringVar(&rocketChatOutput.Query, "rocketchat-output-query", rocketChatOutput.Query, "RocketChat output query")
+
+	rocketChatCmd.AddCommand(&cobra.Command{
+		Use:   "send-message",
+		Short: "Send text message",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("RocketChat sending message...")
+			bytes, err := rocketChatNew(stdout).SendMessage()
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(rocketChatOutput, "RocketChat", []interface{}{rocketChatOptions}, bytes, stdout)
+		},
+	})
+
+	rocketChatCmd.AddCommand(&cobra.Command{
+		Use:   "send-file",
+		Short: "Send file",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("RocketChat sending file...")
+			bytes, err := rocketChatNew(stdout).SendFile()
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(rocketChatOutput, "RocketChat", []interface{}{rocketChatOptions}, bytes, stdout)
+		},
+	})
+
+	addReactionCmd := &cobra.Command{
+		Use:   "add-reaction",
+		Short: "Add reaction",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("RocketChat add reaction...")
+			common.Debug("RocketChat", rocketChatReactionOptions, stdout)
+
+			bytes, err := rocketChatNew(stdout).AddReaction(rocketChatReactionOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(rocketChatOutput, "RocketChat", []interface{}{rocketChatOptions, rocketChatReactionOptions}, bytes, stdout)
+		},
+	}
+	flags = addReactionCmd.PersistentFlags()
+	flags.StringVar(&rocketChatReactionOptions.Name, "rocketchat-reaction-name", rocketChatReactionOptions.Name, "RocketChat reaction name")
+	rocketChatCmd.AddCommand(addReactionCmd)
+
+	return rocketChatCmd
+}
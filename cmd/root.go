@@ -54,8 +54,14 @@ func Execute() {
 
 	rootCmd.AddCommand(NewSlackCommand())
 	rootCmd.AddCommand(NewTelegramCommand())
+	rootCmd.AddCommand(NewMattermostCommand())
+	rootCmd.AddCommand(NewRocketChatCommand())
+	rootCmd.AddCommand(NewDiscordCommand())
+	rootCmd.AddCommand(NewEmailCommand())
+	rootCmd.AddCommand(NewWebhookCommand())
 	rootCmd.AddCommand(NewGraylogCommand())
 	rootCmd.AddCommand(NewJiraCommand())
+	rootCmd.AddCommand(NewConfluenceCommand())
 	rootCmd.AddCommand(NewGrafanaCommand())
 	rootCmd.AddCommand(NewJSONCommand())
 	rootCmd.AddCommand(NewGitlabCommand())
@@ -65,6 +71,7 @@ func Execute() {
 	rootCmd.AddCommand(NewZabbixCommand())
 	rootCmd.AddCommand(NewVCenterCommand())
 	rootCmd.AddCommand(NewPagerDutyCommand())
+	rootCmd.AddCommand(NewOpsgenieCommand())
 	rootCmd.AddCommand(NewAWSCommand())
 
 	rootCmd.AddCommand(NewTemplateCommand())
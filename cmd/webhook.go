@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"strings"
+	"time"
+
+	"github.com/devopsext/tools/common"
+	"github.com/devopsext/tools/render"
+	"github.com/devopsext/tools/vendors"
+	"github.com/devopsext/utils"
+	"github.com/spf13/cobra"
+)
+
+var webhookOptions = vendors.WebhookOptions{
+	Timeout:     envGet("WEBHOOK_TIMEOUT", 30).(int),
+	Insecure:    envGet("WEBHOOK_INSECURE", false).(bool),
+	URL:         envGet("WEBHOOK_URL", "").(string),
+	Method:      envGet("WEBHOOK_METHOD", "POST").(string),
+	Headers:     common.RemoveEmptyStrings(strings.Split(envGet("WEBHOOK_HEADERS", "").(string), "\n")),
+	ContentType: envGet("WEBHOOK_CONTENT_TYPE", "application/json").(string),
+	Body:        envGet("WEBHOOK_BODY", "").(string),
+	Retries:     envGet("WEBHOOK_RETRIES", 0).(int),
+	RetryDelay:  envGet("WEBHOOK_RETRY_DELAY", 1).(int),
+}
+
+var webhookTemplateOptions = render.TemplateOptions{
+	Name:       "webhook",
+	Object:     envGet("WEBHOOK_OBJECT", "").(string),
+	Files:      strings.Split(envGet("WEBHOOK_TEMPLATE_FILES", "").(string), ","),
+	TimeFormat: envGet("WEBHOOK_TIME_FORMAT", time.RFC3339Nano).(string),
+}
+
+var webhookSignatureOptions = vendors.WebhookSignatureOptions{
+	Secret: envGet("WEBHOOK_SIGNATURE_SECRET", "").(string),
+	Style:  envGet("WEBHOOK_SIGNATURE_STYLE", vendors.WebhookSignatureGitHub).(string),
+	Header: envGet("WEBHOOK_SIGNATURE_HEADER", "").(string),
+}
+
+var webhookValidateOptions = vendors.WebhookValidateOptions{
+	Assert: envGet("WEBHOOK_ASSERT", "").(string),
+}
+
+var webhookOutput = common.OutputOptions{
+	Output: envGet("WEBHOOK_OUTPUT", "").(string),
+	Query:  envGet("WEBHOOK_OUTPUT_QUERY", "").(string),
+}
+
+func webhookRenderBody(stdout *common.Stdout) {
+
+	bodyBytes, err := utils.Content(webhookOptions.Body)
+	if err != nil {
+		stdout.Panic(err)
+	}
+	if len(bodyBytes) == 0 {
+		return
+	}
+
+	objectBytes, err := utils.Content(webhookTemplateOptions.Object)
+	if err != nil {
+		stdout.Panic(err)
+	}
+
+	opts := webhookTemplateOptions
+	opts.Content = string(bodyBytes)
+	opts.Object = string(objectBytes)
+	opts.Files = common.RemoveEmptyStrings(opts.Files)
+
+	template, err := render.NewTextTemplate(opts, stdout)
+	if err != nil {
+		stdout.Panic(err)
+	}
+
+	b, err := template.Render()
+	if err != nil {
+		stdout.Panic(err)
+	}
+	webhookOptions.Body = string(b)
+}
+
+func webhookNew(stdout *common.Stdout) *vendors.Webhook {
+
+	common.Debug("Webhook", webhookOptions, stdout)
+	common.Debug("Webhook", webhookOutput, stdout)
+
+	webhookRenderBody(stdout)
+
+	return vendors.NewWebhook(webhookOptions, stdout)
+}
+
+func NewWebhookCommand() *cobra.Command {
+
+	webhookCmd := &cobra.Command{
+		Use:   "webhook",
+		Short: "Webhook tools",
+	}
+
+	flags := webhookCmd.PersistentFlags()
+	flags.IntVar(&webhookOptions.Timeout, "webhook-timeout", webhookOptions.Timeout, "Webhook timeout")
+	flags.BoolVar(&webhookOptions.Insecure, "webhook-insecure", webhookOptions.Insecure, "Webhook insecure")
+	flags.StringVar(&webhookOutput.Output, "webhook-output", webhookOutput.Output, "Webhook output")
+	flags.StringVar(&webhookOutput.Query, "webhook-output-query", webhookOutput.Query, "Webhook output query")
+
+	// tools webhook send --webhook-url https://... --webhook-header "X-Token: ..." --webhook-body '{"text":"{{ .text }}"}' --webhook-object '{"text":"hello"}'
+	sendCmd := &cobra.Command{
+		Use:   "send",
+		Short: "Send webhook",
+		Run: func(cmd *cobra.Command, args []string) {
+
+			stdout.Debug("Webhook sending...")
+			common.Debug("Webhook", webhookSignatureOptions, stdout)
+			common.Debug("Webhook", webhookValidateOptions, stdout)
+
+			bytes, err := webhookNew(stdout).Send(webhookSignatureOptions, webhookValidateOptions)
+			if err != nil {
+				stdout.Error(err)
+				return
+			}
+			common.OutputJson(webhookOutput, "Webhook", []interface{}{webhookOptions, webhookValidateOptions}, bytes, stdout)
+		},
+	}
+	flags = sendCmd.PersistentFlags()
+	flags.StringVar(&webhookOptions.URL, "webhook-url", webhookOptions.URL, "Webhook URL")
+	flags.StringVar(&webhookOptions.Method, "webhook-method", webhookOptions.Method, "Webhook method")
+	flags.StringArrayVar(&webhookOptions.Headers, "webhook-header", webhookOptions.Headers, "Webhook header (Name: value), can be repeated")
+	flags.StringVar(&webhookOptions.ContentType, "webhook-content-type", webhookOptions.ContentType, "Webhook content type")
+	flags.StringVar(&webhookOptions.Body, "webhook-body", webhookOptions.Body, "Webhook body template content or path")
+	flags.StringVar(&webhookTemplateOptions.Object, "webhook-object", webhookTemplateOptions.Object, "Webhook body template object: json content or path")
+	flags.StringSliceVar(&webhookTemplateOptions.Files, "webhook-template-files", webhookTemplateOptions.Files, "Webhook body template files")
+	flags.StringVar(&webhookTemplateOptions.TimeFormat, "webhook-time-format", webhookTemplateOptions.TimeFormat, "Webhook body template time format")
+	flags.IntVar(&webhookOptions.Retries, "webhook-retries", webhookOptions.Retries, "Webhook retries on network errors, 429 and 5xx which are not expected")
+	flags.IntVar(&webhookOptions.RetryDelay, "webhook-retry-delay", webhookOptions.RetryDelay, "Webhook delay between retries in seconds")
+	flags.StringVar(&webhookSignatureOptions.Secret, "webhook-signature-secret", webhookSignatureOptions.Secret, "Webhook HMAC-SHA256 signature secret")
+	flags.StringVar(&webhookSignatureOptions.Style, "webhook-signature-style", webhookSignatureOptions.Style, "Webhook signature style: github, stripe")
+	flags.StringVar(&webhookSignatureOptions.Header, "webhook-signature-header", webhookSignatureOptions.Header, "Webhook signature header (X-Hub-Signature-256 or Stripe-Signature if empty)")
+	flags.IntSliceVar(&webhookValidateOptions.Codes, "webhook-expect-codes", webhookValidateOptions.Codes, "Webhook expected response codes (any 2xx if empty)")
+	flags.StringVar(&webhookValidateOptions.Assert, "webhook-assert", webhookValidateOptions.Assert, "Webhook jsonata assertion on response body, must evaluate to true")
+	webhookCmd.AddCommand(sendCmd)
+
+	return webhookCmd
+}
@@ -828,6 +828,8 @@ func (tpl *Template) JiraSearchAssets(params map[string]interface{}) ([]byte, er
 	user, _ := params["user"].(string)
 	password, _ := params["password"].(string)
 	token, _ := params["token"].(string)
+	email, _ := params["email"].(string)
+	apiToken, _ := params["apiToken"].(string)
 
 	jiraOptions := vendors.JiraOptions{
 		URL:         url,
@@ -836,6 +838,8 @@ func (tpl *Template) JiraSearchAssets(params map[string]interface{}) ([]byte, er
 		User:        user,
 		Password:    password,
 		AccessToken: token,
+		Email:       email,
+		APIToken:    apiToken,
 	}
 
 	jira := vendors.NewJira(jiraOptions)
@@ -846,9 +850,12 @@ func (tpl *Template) JiraSearchAssets(params map[string]interface{}) ([]byte, er
 		limit = 50
 	}
 
+	flatten, _ := params["flatten"].(bool)
+
 	assetsOptions := vendors.JiraSearchAssetsOptions{
 		SearchPattern: query,
 		ResultPerPage: limit,
+		Flatten:       flatten,
 	}
 
 	return jira.SearchAssets(assetsOptions)
@@ -865,19 +872,38 @@ func (tpl *Template) JiraCreateAsset(params map[string]interface{}) ([]byte, err
 	user, _ := params["user"].(string)
 	password, _ := params["password"].(string)
 	token, _ := params["token"].(string)
+	email, _ := params["email"].(string)
+	apiToken, _ := params["apiToken"].(string)
 
 	objectTypeId, _ := params["objectTypeId"].(int)
 	objectSchemeId, _ := params["objectSchemeId"].(string)
-	repositoryId, _ := params["repositoryId"].(int)
-	nameId, _ := params["nameId"].(int)
-	descriptionId, _ := params["descriptionId"].(int)
-	description, _ := params["description"].(string)
-	name, _ := params["name"].(string)
-	repository, _ := params["repository"].(string)
-	titleId, _ := params["titleId"].(int)
-	title, _ := params["title"].(string)
-	tierId, _ := params["tierId"].(int)
-	tier, _ := params["tier"].(string)
+
+	// attributes are map of attribute name or id to value, legacy params are mapped by their ids
+	attributes := make(map[string]interface{})
+	switch v := params["attributes"].(type) {
+	case map[string]interface{}:
+		for k, a := range v {
+			attributes[k] = a
+		}
+	case string:
+		m, err := common.ReadAndMarshal(v)
+		if err != nil {
+			return nil, err
+		}
+		attributes = m
+	}
+	for _, name := range []string{"name", "description", "repository", "title", "tier"} {
+		id, _ := params[name+"Id"].(int)
+		value, ok := params[name].(string)
+		if id > 0 && ok {
+			attributes[strconv.Itoa(id)] = value
+		}
+	}
+
+	attributesBytes, err := json.Marshal(attributes)
+	if err != nil {
+		return nil, err
+	}
 
 	jiraOptions := vendors.JiraOptions{
 		URL:         url,
@@ -886,20 +912,13 @@ func (tpl *Template) JiraCreateAsset(params map[string]interface{}) ([]byte, err
 		User:        user,
 		Password:    password,
 		AccessToken: token,
+		Email:       email,
+		APIToken:    apiToken,
 	}
 	jiraIssueOptions := vendors.JiraCreateAssetOptions{
-		Name:           name,
 		ObjectSchemeId: objectSchemeId,
 		ObjectTypeId:   objectTypeId,
-		RepositoryId:   repositoryId,
-		NameId:         nameId,
-		DescriptionId:  descriptionId,
-		Description:    description,
-		Repository:     repository,
-		TitleId:        titleId,
-		Title:          title,
-		TierId:         tierId,
-		Tier:           tier,
+		Attributes:     string(attributesBytes),
 	}
 
 	jira := vendors.NewJira(jiraOptions)
@@ -923,6 +942,9 @@ func (tpl *Template) JiraCreateIssue(params map[string]interface{}) ([]byte, err
 	user, _ := params["user"].(string)
 	password, _ := params["password"].(string)
 	token, _ := params["token"].(string)
+	email, _ := params["email"].(string)
+	apiToken, _ := params["apiToken"].(string)
+	apiVersion, _ := params["apiVersion"].(int)
 
 	key, _ := params["projectKey"].(string)
 	summary, _ := params["summary"].(string)
@@ -939,6 +961,9 @@ func (tpl *Template) JiraCreateIssue(params map[string]interface{}) ([]byte, err
 		User:        user,
 		Password:    password,
 		AccessToken: token,
+		Email:       email,
+		APIToken:    apiToken,
+		APIVersion:  apiVersion,
 	}
 	jiraIssueOptions := vendors.JiraIssueOptions{
 		ProjectKey:   key,
@@ -1006,6 +1031,70 @@ func (tpl *Template) PagerDutyCreateIncident(params map[string]interface{}) ([]b
 	return pagerDuty.CreateIncident(incidentOptions, createOptions)
 }
 
+func (tpl *Template) paramStrings(v interface{}) []string {
+
+	switch vv := v.(type) {
+	case string:
+		return strings.Split(vv, ",")
+	case []string:
+		return vv
+	case []interface{}:
+		var r []string
+		for _, i := range vv {
+			r = append(r, fmt.Sprintf("%v", i))
+		}
+		return r
+	}
+	return nil
+}
+
+func (tpl *Template) OpsgenieCreateAlert(params map[string]interface{}) ([]byte, error) {
+
+	if len(params) == 0 {
+		return nil, fmt.Errorf("OpsgenieCreateAlert err => %s", "no params allowed")
+	}
+
+	url, _ := params["url"].(string)
+	if url == "" {
+		url = "https://api.opsgenie.com"
+	}
+	timeout, _ := params["timeout"].(int)
+	if timeout == 0 {
+		timeout = 10
+	}
+	insecure, _ := params["insecure"].(bool)
+	token, _ := params["token"].(string)
+
+	opsgenieOptions := vendors.OpsgenieOptions{
+		URL:      url,
+		Timeout:  timeout,
+		Insecure: insecure,
+		Token:    token,
+	}
+
+	opsgenie := vendors.NewOpsgenie(opsgenieOptions, tpl.logger)
+
+	title, _ := params["title"].(string)
+	body, _ := params["body"].(string)
+	priority, _ := params["priority"].(string)
+	alias, _ := params["alias"].(string)
+	source, _ := params["source"].(string)
+	entity, _ := params["entity"].(string)
+
+	alertOptions := vendors.OpsgenieAlertOptions{
+		Title:      title,
+		Body:       body,
+		Priority:   priority,
+		Responders: tpl.paramStrings(params["responders"]),
+		Alias:      alias,
+		Tags:       tpl.paramStrings(params["tags"]),
+		Source:     source,
+		Entity:     entity,
+	}
+
+	return opsgenie.CreateAlert(alertOptions)
+}
+
 func (tpl *Template) TemplateRender(name string, obj interface{}) (string, error) {
 
 	opts := TemplateOptions{
@@ -1246,6 +1335,7 @@ func (tpl *Template) setTemplateFuncs(funcs map[string]any) {
 	funcs["jiraCreateIssue"] = tpl.JiraCreateIssue
 	funcs["jiraCreateAsset"] = tpl.JiraCreateAsset
 	funcs["pagerDutyCreateIncident"] = tpl.PagerDutyCreateIncident
+	funcs["opsgenieCreateAlert"] = tpl.OpsgenieCreateAlert
 	funcs["templateRender"] = tpl.TemplateRender
 	funcs["templateRenderFile"] = tpl.TemplateRenderFile
 	funcs["googleCalendarGetEvents"] = tpl.GoogleCalendarGetEvents
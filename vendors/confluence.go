@@ -0,0 +1,405 @@
+package vendors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/devopsext/utils"
+)
+
+// Confluence shares URL and auth options with Jira, URL should include context path like /wiki for Cloud
+type Confluence struct {
+	client  *http.Client
+	options JiraOptions
+	jira    *Jira
+}
+
+type ConfluencePageOptions struct {
+	ID             string
+	SpaceKey       string
+	Title          string
+	ParentID       string
+	Body           string
+	Representation string // storage, wiki
+	VersionMessage string
+	Labels         []string
+}
+
+type ConfluenceAddAttachmentOptions struct {
+	File    string
+	Name    string
+	Comment string
+}
+
+type ConfluenceLabelsOptions struct {
+	Add    []string
+	Remove []string
+}
+
+type ConfluenceSpace struct {
+	Key string `json:"key"`
+}
+
+type ConfluenceVersion struct {
+	Number  int    `json:"number"`
+	Message string `json:"message,omitempty"`
+}
+
+type ConfluencePageRef struct {
+	ID string `json:"id"`
+}
+
+type ConfluenceBodyValue struct {
+	Value          string `json:"value"`
+	Representation string `json:"representation"`
+}
+
+type ConfluencePage struct {
+	ID        string                          `json:"id,omitempty"`
+	Type      string                          `json:"type"`
+	Title     string                          `json:"title"`
+	Space     *ConfluenceSpace                `json:"space,omitempty"`
+	Version   *ConfluenceVersion              `json:"version,omitempty"`
+	Ancestors []*ConfluencePageRef            `json:"ancestors,omitempty"`
+	Body      map[string]*ConfluenceBodyValue `json:"body,omitempty"`
+}
+
+type ConfluenceLabel struct {
+	Prefix string `json:"prefix"`
+	Name   string `json:"name"`
+}
+
+const (
+	confluencePageVersionRetries = 3
+	confluencePageExpand         = "version,space"
+)
+
+func (c *Confluence) request(confluenceOptions JiraOptions, method, p string, params url.Values, contentType string, body []byte) ([]byte, int, error) {
+
+	u, err := url.Parse(confluenceOptions.URL)
+	if err != nil {
+		return nil, 0, err
+	}
+	u.Path = path.Join(u.Path, "rest/api", p)
+	if params != nil {
+		u.RawQuery = params.Encode()
+	}
+
+	headers := make(map[string]string)
+	headers["Content-Type"] = contentType
+	headers["Authorization"] = c.jira.getAuth(confluenceOptions)
+	headers["X-Atlassian-Token"] = "no-check"
+
+	b, code, err := utils.HttpRequestRawWithHeadersOutCode(c.client, method, u.String(), headers, body)
+	if err != nil {
+		return b, code, responseError(b, err)
+	}
+	return b, code, nil
+}
+
+// findPage looks for page by id or by space and title, returns nil if page doesn't exist
+func (c *Confluence) findPage(confluenceOptions JiraOptions, pageOptions ConfluencePageOptions, expand string) (*ConfluencePage, []byte, error) {
+
+	params := make(url.Values)
+	params.Add("expand", expand)
+
+	if !utils.IsEmpty(pageOptions.ID) {
+		b, code, err := c.request(confluenceOptions, http.MethodGet, path.Join("content", url.PathEscape(pageOptions.ID)), params, "application/json", nil)
+		if code == http.StatusNotFound {
+			return nil, nil, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		var page ConfluencePage
+		if err := json.Unmarshal(b, &page); err != nil {
+			return nil, nil, err
+		}
+		return &page, b, nil
+	}
+
+	if utils.IsEmpty(pageOptions.SpaceKey) || utils.IsEmpty(pageOptions.Title) {
+		return nil, nil, fmt.Errorf("confluence page id or space and title are required")
+	}
+
+	params.Add("type", "page")
+	params.Add("spaceKey", pageOptions.SpaceKey)
+	params.Add("title", pageOptions.Title)
+
+	b, _, err := c.request(confluenceOptions, http.MethodGet, "content", params, "application/json", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var r struct {
+		Results []json.RawMessage `json:"results"`
+	}
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, nil, err
+	}
+	if len(r.Results) == 0 {
+		return nil, nil, nil
+	}
+
+	var page ConfluencePage
+	if err := json.Unmarshal(r.Results[0], &page); err != nil {
+		return nil, nil, err
+	}
+	return &page, r.Results[0], nil
+}
+
+func (c *Confluence) getPage(confluenceOptions JiraOptions, pageOptions ConfluencePageOptions, expand string) (*ConfluencePage, []byte, error) {
+
+	page, b, err := c.findPage(confluenceOptions, pageOptions, expand)
+	if err != nil {
+		return nil, nil, err
+	}
+	if page == nil {
+		if !utils.IsEmpty(pageOptions.ID) {
+			return nil, nil, fmt.Errorf("confluence page %s is not found", pageOptions.ID)
+		}
+		return nil, nil, fmt.Errorf("confluence page %s is not found in space %s", pageOptions.Title, pageOptions.SpaceKey)
+	}
+	return page, b, nil
+}
+
+func confluenceBody(pageOptions ConfluencePageOptions) map[string]*ConfluenceBodyValue {
+
+	representation := pageOptions.Representation
+	if utils.IsEmpty(representation) {
+		representation = "storage"
+	}
+	return map[string]*ConfluenceBodyValue{
+		representation: {
+			Value:          pageOptions.Body,
+			Representation: representation,
+		},
+	}
+}
+
+func (c *Confluence) CustomCreatePage(confluenceOptions JiraOptions, pageOptions ConfluencePageOptions) ([]byte, error) {
+
+	if utils.IsEmpty(pageOptions.SpaceKey) || utils.IsEmpty(pageOptions.Title) {
+		return nil, fmt.Errorf("confluence page space and title are required")
+	}
+
+	page := &ConfluencePage{
+		Type:  "page",
+		Title: pageOptions.Title,
+		Space: &ConfluenceSpace{Key: pageOptions.SpaceKey},
+		Body:  confluenceBody(pageOptions),
+	}
+	if !utils.IsEmpty(pageOptions.ParentID) {
+		page.Ancestors = []*ConfluencePageRef{{ID: pageOptions.ParentID}}
+	}
+
+	req, err := json.Marshal(page)
+	if err != nil {
+		return nil, err
+	}
+
+	b, _, err := c.request(confluenceOptions, http.MethodPost, "content", nil, "application/json", req)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pageOptions.Labels) > 0 {
+		var created ConfluencePage
+		if err := json.Unmarshal(b, &created); err != nil {
+			return nil, err
+		}
+		if err := c.addLabels(confluenceOptions, created.ID, pageOptions.Labels); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func (c *Confluence) CreatePage(pageOptions ConfluencePageOptions) ([]byte, error) {
+	return c.CustomCreatePage(c.options, pageOptions)
+}
+
+// CustomUpdatePage bumps current page version, it's reread and retried if page was changed concurrently
+func (c *Confluence) CustomUpdatePage(confluenceOptions JiraOptions, pageOptions ConfluencePageOptions) ([]byte, error) {
+
+	for attempt := 1; ; attempt++ {
+
+		current, _, err := c.getPage(confluenceOptions, pageOptions, confluencePageExpand)
+		if err != nil {
+			return nil, err
+		}
+
+		page := &ConfluencePage{
+			ID:    current.ID,
+			Type:  "page",
+			Title: current.Title,
+			Space: current.Space,
+			Body:  confluenceBody(pageOptions),
+			Version: &ConfluenceVersion{
+				Number:  1,
+				Message: pageOptions.VersionMessage,
+			},
+		}
+		if current.Version != nil {
+			page.Version.Number = current.Version.Number + 1
+		}
+		// page found by id can be renamed
+		if !utils.IsEmpty(pageOptions.ID) && !utils.IsEmpty(pageOptions.Title) {
+			page.Title = pageOptions.Title
+		}
+		if !utils.IsEmpty(pageOptions.ParentID) {
+			page.Ancestors = []*ConfluencePageRef{{ID: pageOptions.ParentID}}
+		}
+
+		req, err := json.Marshal(page)
+		if err != nil {
+			return nil, err
+		}
+
+		b, code, err := c.request(confluenceOptions, http.MethodPut, path.Join("content", url.PathEscape(current.ID)), nil, "application/json", req)
+		if code == http.StatusConflict && attempt < confluencePageVersionRetries {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(pageOptions.Labels) > 0 {
+			if err := c.addLabels(confluenceOptions, current.ID, pageOptions.Labels); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	}
+}
+
+func (c *Confluence) UpdatePage(pageOptions ConfluencePageOptions) ([]byte, error) {
+	return c.CustomUpdatePage(c.options, pageOptions)
+}
+
+func (c *Confluence) CustomGetPage(confluenceOptions JiraOptions, pageOptions ConfluencePageOptions) ([]byte, error) {
+
+	_, b, err := c.getPage(confluenceOptions, pageOptions, "body.storage,version,space,ancestors,metadata.labels")
+	return b, err
+}
+
+func (c *Confluence) GetPage(pageOptions ConfluencePageOptions) ([]byte, error) {
+	return c.CustomGetPage(c.options, pageOptions)
+}
+
+// CustomAddPageAttachment creates attachment or adds new version of attachment with the same name
+func (c *Confluence) CustomAddPageAttachment(confluenceOptions JiraOptions, pageOptions ConfluencePageOptions, attachmentOptions ConfluenceAddAttachmentOptions) ([]byte, error) {
+
+	page, _, err := c.getPage(confluenceOptions, pageOptions, confluencePageExpand)
+	if err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+
+	fw, err := w.CreateFormFile("file", attachmentOptions.Name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fw.Write([]byte(attachmentOptions.File)); err != nil {
+		return nil, err
+	}
+	if !utils.IsEmpty(attachmentOptions.Comment) {
+		if err := w.WriteField("comment", attachmentOptions.Comment); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.WriteField("minorEdit", "true"); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	b, _, err := c.request(confluenceOptions, http.MethodPut, path.Join("content", url.PathEscape(page.ID), "child/attachment"), nil, w.FormDataContentType(), body.Bytes())
+	return b, err
+}
+
+func (c *Confluence) AddPageAttachment(pageOptions ConfluencePageOptions, attachmentOptions ConfluenceAddAttachmentOptions) ([]byte, error) {
+	return c.CustomAddPageAttachment(c.options, pageOptions, attachmentOptions)
+}
+
+func (c *Confluence) addLabels(confluenceOptions JiraOptions, id string, labels []string) error {
+
+	var list []*ConfluenceLabel
+	for _, l := range labels {
+		l = strings.TrimSpace(l)
+		if utils.IsEmpty(l) {
+			continue
+		}
+		list = append(list, &ConfluenceLabel{Prefix: "global", Name: l})
+	}
+	if len(list) == 0 {
+		return nil
+	}
+
+	req, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+	_, _, err = c.request(confluenceOptions, http.MethodPost, path.Join("content", url.PathEscape(id), "label"), nil, "application/json", req)
+	return err
+}
+
+func (c *Confluence) removeLabel(confluenceOptions JiraOptions, id string, label string) error {
+
+	params := make(url.Values)
+	params.Add("name", label)
+
+	_, code, err := c.request(confluenceOptions, http.MethodDelete, path.Join("content", url.PathEscape(id), "label"), params, "application/json", nil)
+	if code == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+// CustomUpdatePageLabels adds and removes labels, returns resulting page labels
+func (c *Confluence) CustomUpdatePageLabels(confluenceOptions JiraOptions, pageOptions ConfluencePageOptions, labelsOptions ConfluenceLabelsOptions) ([]byte, error) {
+
+	page, _, err := c.getPage(confluenceOptions, pageOptions, confluencePageExpand)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.addLabels(confluenceOptions, page.ID, labelsOptions.Add); err != nil {
+		return nil, err
+	}
+	for _, l := range labelsOptions.Remove {
+		l = strings.TrimSpace(l)
+		if utils.IsEmpty(l) {
+			continue
+		}
+		if err := c.removeLabel(confluenceOptions, page.ID, l); err != nil {
+			return nil, err
+		}
+	}
+
+	b, _, err := c.request(confluenceOptions, http.MethodGet, path.Join("content", url.PathEscape(page.ID), "label"), nil, "application/json", nil)
+	return b, err
+}
+
+func (c *Confluence) UpdatePageLabels(pageOptions ConfluencePageOptions, labelsOptions ConfluenceLabelsOptions) ([]byte, error) {
+	return c.CustomUpdatePageLabels(c.options, pageOptions, labelsOptions)
+}
+
+func NewConfluence(options JiraOptions) *Confluence {
+
+	confluence := &Confluence{
+		client:  utils.NewHttpClient(options.Timeout, options.Insecure),
+		options: options,
+		jira:    NewJira(options),
+	}
+	return confluence
+}
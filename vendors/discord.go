@@ -0,0 +1,239 @@
+package vendors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/devopsext/utils"
+)
+
+type DiscordOptions struct {
+	Timeout    int
+	Insecure   bool
+	WebhookURL string
+	Username   string
+	AvatarURL  string
+	ThreadID   string
+	Title      string
+	Message    string
+	Severity   string
+	Color      string
+	ImageURL   string
+	Fields     []string // name=value
+	FileName   string
+	File       string // content or path to file
+}
+
+type DiscordEmbedImage struct {
+	URL string `json:"url"`
+}
+
+type DiscordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+type DiscordEmbed struct {
+	Title       string               `json:"title,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Color       int                  `json:"color,omitempty"`
+	Image       *DiscordEmbedImage   `json:"image,omitempty"`
+	Fields      []*DiscordEmbedField `json:"fields,omitempty"`
+}
+
+type DiscordMessage struct {
+	Content   string          `json:"content,omitempty"`
+	Username  string          `json:"username,omitempty"`
+	AvatarURL string          `json:"avatar_url,omitempty"`
+	Embeds    []*DiscordEmbed `json:"embeds,omitempty"`
+}
+
+type Discord struct {
+	client  *http.Client
+	options DiscordOptions
+}
+
+var discordSeverityColors = map[string]string{
+	"critical": "#d50000",
+	"error":    "#ff5252",
+	"warning":  "#ffab00",
+	"info":     "#2196f3",
+	"ok":       "#00c853",
+	"resolved": "#00c853",
+}
+
+func (d *Discord) getColor(opts DiscordOptions) (int, error) {
+
+	color := opts.Color
+	if utils.IsEmpty(color) {
+		color = discordSeverityColors[strings.ToLower(strings.TrimSpace(opts.Severity))]
+	}
+	if utils.IsEmpty(color) {
+		return 0, nil
+	}
+
+	c, err := strconv.ParseInt(strings.TrimPrefix(color, "#"), 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("discord color %s is invalid", color)
+	}
+	return int(c), nil
+}
+
+func (d *Discord) getFields(opts DiscordOptions) []*DiscordEmbedField {
+
+	var r []*DiscordEmbedField
+	for _, f := range opts.Fields {
+
+		if utils.IsEmpty(f) {
+			continue
+		}
+		kv := strings.SplitN(f, "=", 2)
+		field := &DiscordEmbedField{
+			Name:   strings.TrimSpace(kv[0]),
+			Inline: true,
+		}
+		if len(kv) > 1 {
+			field.Value = strings.TrimSpace(kv[1])
+		}
+		r = append(r, field)
+	}
+	return r
+}
+
+func (d *Discord) getWebhookURL(opts DiscordOptions) (string, error) {
+
+	u, err := url.Parse(opts.WebhookURL)
+	if err != nil {
+		return "", err
+	}
+
+	params := u.Query()
+	params.Set("wait", "true")
+	if !utils.IsEmpty(opts.ThreadID) {
+		params.Set("thread_id", opts.ThreadID)
+	}
+	u.RawQuery = params.Encode()
+	return u.String(), nil
+}
+
+func (d *Discord) prepareMessage(opts DiscordOptions) (*DiscordMessage, error) {
+
+	m := &DiscordMessage{
+		Username:  opts.Username,
+		AvatarURL: opts.AvatarURL,
+	}
+
+	color, err := d.getColor(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	embed := &DiscordEmbed{
+		Title:       opts.Title,
+		Description: opts.Message,
+		Color:       color,
+		Fields:      d.getFields(opts),
+	}
+	if !utils.IsEmpty(opts.ImageURL) {
+		embed.Image = &DiscordEmbedImage{URL: opts.ImageURL}
+	}
+
+	if !utils.IsEmpty(embed.Title) || !utils.IsEmpty(embed.Description) || embed.Image != nil || len(embed.Fields) > 0 {
+		m.Embeds = []*DiscordEmbed{embed}
+	}
+	return m, nil
+}
+
+func (d *Discord) CustomSendMessage(discordOptions DiscordOptions) ([]byte, error) {
+
+	if utils.IsEmpty(discordOptions.Message) {
+		return nil, errors.New("discord message is empty")
+	}
+
+	m, err := d.prepareMessage(discordOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := d.getWebhookURL(discordOptions)
+	if err != nil {
+		return nil, err
+	}
+	return utils.HttpPostRaw(d.client, u, "application/json", "", req)
+}
+
+func (d *Discord) SendMessage() ([]byte, error) {
+	return d.CustomSendMessage(d.options)
+}
+
+func (d *Discord) CustomSendFile(discordOptions DiscordOptions) ([]byte, error) {
+
+	if utils.IsEmpty(discordOptions.FileName) {
+		return nil, errors.New("discord file name is empty")
+	}
+
+	m, err := d.prepareMessage(discordOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	defer func() {
+		w.Close()
+	}()
+
+	if err := w.WriteField("payload_json", string(payload)); err != nil {
+		return nil, err
+	}
+
+	fw, err := w.CreateFormFile("files[0]", discordOptions.FileName)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := fw.Write([]byte(discordOptions.File)); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	u, err := d.getWebhookURL(discordOptions)
+	if err != nil {
+		return nil, err
+	}
+	return utils.HttpPostRaw(d.client, u, w.FormDataContentType(), "", body.Bytes())
+}
+
+func (d *Discord) SendFile() ([]byte, error) {
+	return d.CustomSendFile(d.options)
+}
+
+func NewDiscord(options DiscordOptions) *Discord {
+
+	discord := &Discord{
+		client:  utils.NewHttpClient(options.Timeout, options.Insecure),
+		options: options,
+	}
+	return discord
+}
@@ -0,0 +1,522 @@
+package vendors
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devopsext/tools/common"
+	"github.com/devopsext/utils"
+	"github.com/google/uuid"
+)
+
+const (
+	emailTLSAuto     = "auto"
+	emailTLSNone     = "none"
+	emailTLSStartTLS = "starttls"
+	emailTLSImplicit = "tls"
+
+	emailAuthPlain = "plain"
+	emailAuthLogin = "login"
+
+	emailLineLength = 76
+)
+
+type EmailOptions struct {
+	Timeout  int
+	Insecure bool
+	Host     string
+	Port     int
+	User     string
+	Password string
+	Auth     string // plain, login
+	TLS      string // auto, none, starttls, tls
+}
+
+type EmailSendOptions struct {
+	From         string
+	To           []string
+	Cc           []string
+	Bcc          []string
+	Subject      string
+	Body         string   // html content
+	Text         string   // plain text alternative, generated from body if empty
+	Attachments  []string // paths to files
+	InlineImages []string // cid=path or path, cid is the file name in that case
+}
+
+type EmailSendResponse struct {
+	MessageID  string   `json:"messageId"`
+	From       string   `json:"from"`
+	Recipients []string `json:"recipients"`
+}
+
+type emailFile struct {
+	name    string
+	cid     string
+	content []byte
+}
+
+type Email struct {
+	options EmailOptions
+	logger  common.Logger
+}
+
+// net/smtp supports PLAIN and CRAM-MD5 only
+type emailLoginAuth struct {
+	user     string
+	password string
+}
+
+// Start refuses unencrypted connections except localhost as smtp.PlainAuth does, credentials are sent as is
+func (a *emailLoginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+
+	if !server.TLS && !emailIsLocalhost(server.Name) {
+		return "", nil, errors.New("email login auth requires encrypted connection")
+	}
+	return "LOGIN", []byte{}, nil
+}
+
+func emailIsLocalhost(name string) bool {
+	return name == "localhost" || name == "127.0.0.1" || name == "::1"
+}
+
+func (a *emailLoginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+
+	if !more {
+		return nil, nil
+	}
+
+	prompt := strings.ToLower(strings.TrimSpace(string(fromServer)))
+	switch {
+	case strings.HasPrefix(prompt, "username"):
+		return []byte(a.user), nil
+	case strings.HasPrefix(prompt, "password"):
+		return []byte(a.password), nil
+	default:
+		return nil, fmt.Errorf("unexpected server challenge: %s", fromServer)
+	}
+}
+
+var emailHtmlTags = regexp.MustCompile(`(?s)<(style|script|head)[^>]*>.*?</(style|script|head)>|<[^>]+>`)
+var emailBlankLines = regexp.MustCompile(`\n\s*\n\s*\n+`)
+var emailLineBreaks = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>|</tr>|</h[1-6]>|</li>`)
+
+func (e *Email) htmlToText(s string) string {
+
+	s = emailLineBreaks.ReplaceAllString(s, "\n")
+	s = emailHtmlTags.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+
+	var lines []string
+	for _, l := range strings.Split(s, "\n") {
+		lines = append(lines, strings.TrimSpace(l))
+	}
+	s = strings.Join(lines, "\n")
+	s = emailBlankLines.ReplaceAllString(s, "\n\n")
+	return strings.TrimSpace(s)
+}
+
+func (e *Email) readFiles(items []string, inline bool) ([]*emailFile, error) {
+
+	var r []*emailFile
+	for _, item := range items {
+
+		if utils.IsEmpty(item) {
+			continue
+		}
+
+		cid := ""
+		p := strings.TrimSpace(item)
+		if inline {
+			kv := strings.SplitN(p, "=", 2)
+			if len(kv) > 1 {
+				cid = strings.TrimSpace(kv[0])
+				p = strings.TrimSpace(kv[1])
+			}
+		}
+
+		if !utils.FileExists(p) {
+			return nil, fmt.Errorf("email file %s not found", p)
+		}
+
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+
+		name := filepath.Base(p)
+		if inline && utils.IsEmpty(cid) {
+			cid = name
+		}
+		r = append(r, &emailFile{name: name, cid: cid, content: b})
+	}
+	return r, nil
+}
+
+func (e *Email) writeBase64(w *bytes.Buffer, b []byte) {
+
+	s := base64.StdEncoding.EncodeToString(b)
+	for len(s) > emailLineLength {
+		w.WriteString(s[:emailLineLength])
+		w.WriteString("\r\n")
+		s = s[emailLineLength:]
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
+
+func (e *Email) writePart(w *multipart.Writer, contentType, encoding string, extra map[string]string, body []byte) error {
+
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Type", contentType)
+	if !utils.IsEmpty(encoding) {
+		h.Set("Content-Transfer-Encoding", encoding)
+	}
+	for k, v := range extra {
+		h.Set(k, v)
+	}
+
+	pw, err := w.CreatePart(h)
+	if err != nil {
+		return err
+	}
+
+	b := &bytes.Buffer{}
+	if encoding == "base64" {
+		e.writeBase64(b, body)
+	} else {
+		b.Write(body)
+	}
+	_, err = pw.Write(b.Bytes())
+	return err
+}
+
+func (e *Email) alternativePart(sendOptions EmailSendOptions) ([]byte, string, error) {
+
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+
+	text := sendOptions.Text
+	if utils.IsEmpty(text) {
+		text = e.htmlToText(sendOptions.Body)
+	}
+
+	if err := e.writePart(w, "text/plain; charset=UTF-8", "base64", nil, []byte(text)); err != nil {
+		return nil, "", err
+	}
+
+	if !utils.IsEmpty(sendOptions.Body) {
+		if err := e.writePart(w, "text/html; charset=UTF-8", "base64", nil, []byte(sendOptions.Body)); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	return b.Bytes(), fmt.Sprintf("multipart/alternative; boundary=%s", w.Boundary()), nil
+}
+
+func (e *Email) relatedPart(sendOptions EmailSendOptions, inline []*emailFile) ([]byte, string, error) {
+
+	alt, altType, err := e.alternativePart(sendOptions)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(inline) == 0 {
+		return alt, altType, nil
+	}
+
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+
+	if err := e.writePart(w, altType, "", nil, alt); err != nil {
+		return nil, "", err
+	}
+
+	for _, f := range inline {
+		extra := map[string]string{
+			"Content-ID":          fmt.Sprintf("<%s>", f.cid),
+			"Content-Disposition": mime.FormatMediaType("inline", map[string]string{"filename": f.name}),
+		}
+		if err := e.writePart(w, e.contentType(f.name), "base64", extra, f.content); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	return b.Bytes(), fmt.Sprintf("multipart/related; boundary=%s", w.Boundary()), nil
+}
+
+func (e *Email) contentType(name string) string {
+
+	t := mime.TypeByExtension(filepath.Ext(name))
+	if utils.IsEmpty(t) {
+		t = "application/octet-stream"
+	}
+	return t
+}
+
+func (e *Email) domain(address string) string {
+
+	arr := strings.Split(address, "@")
+	if len(arr) > 1 {
+		return strings.TrimRight(arr[len(arr)-1], ">")
+	}
+	return "localhost"
+}
+
+func (e *Email) buildMessage(sendOptions EmailSendOptions, messageID string) ([]byte, error) {
+
+	inline, err := e.readFiles(sendOptions.InlineImages, true)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, err := e.readFiles(sendOptions.Attachments, false)
+	if err != nil {
+		return nil, err
+	}
+
+	body, bodyType, err := e.relatedPart(sendOptions, inline)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+
+	headers := []string{
+		fmt.Sprintf("From: %s", sendOptions.From),
+		fmt.Sprintf("To: %s", strings.Join(common.RemoveEmptyStrings(sendOptions.To), ", ")),
+	}
+	cc := common.RemoveEmptyStrings(sendOptions.Cc)
+	if len(cc) > 0 {
+		headers = append(headers, fmt.Sprintf("Cc: %s", strings.Join(cc, ", ")))
+	}
+	headers = append(headers,
+		fmt.Sprintf("Subject: %s", mime.QEncoding.Encode("UTF-8", sendOptions.Subject)),
+		fmt.Sprintf("Date: %s", time.Now().Format(time.RFC1123Z)),
+		fmt.Sprintf("Message-ID: %s", messageID),
+		"MIME-Version: 1.0",
+	)
+
+	if len(attachments) == 0 {
+		headers = append(headers, fmt.Sprintf("Content-Type: %s", bodyType))
+		b.WriteString(strings.Join(headers, "\r\n"))
+		b.WriteString("\r\n\r\n")
+		b.Write(body)
+		return b.Bytes(), nil
+	}
+
+	var mixed bytes.Buffer
+	w := multipart.NewWriter(&mixed)
+
+	if err := e.writePart(w, bodyType, "", nil, body); err != nil {
+		return nil, err
+	}
+
+	for _, f := range attachments {
+		extra := map[string]string{
+			"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": f.name}),
+		}
+		if err := e.writePart(w, e.contentType(f.name), "base64", extra, f.content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	headers = append(headers, fmt.Sprintf("Content-Type: multipart/mixed; boundary=%s", w.Boundary()))
+	b.WriteString(strings.Join(headers, "\r\n"))
+	b.WriteString("\r\n\r\n")
+	b.Write(mixed.Bytes())
+	return b.Bytes(), nil
+}
+
+func (e *Email) getAuth(opts EmailOptions) (smtp.Auth, error) {
+
+	if utils.IsEmpty(opts.User) {
+		return nil, nil
+	}
+
+	switch strings.ToLower(opts.Auth) {
+	case "", emailAuthPlain:
+		return smtp.PlainAuth("", opts.User, opts.Password, opts.Host), nil
+	case emailAuthLogin:
+		return &emailLoginAuth{user: opts.User, password: opts.Password}, nil
+	default:
+		return nil, fmt.Errorf("email auth %s is not supported", opts.Auth)
+	}
+}
+
+func (e *Email) dial(opts EmailOptions) (*smtp.Client, error) {
+
+	addr := net.JoinHostPort(opts.Host, strconv.Itoa(opts.Port))
+	timeout := time.Duration(opts.Timeout) * time.Second
+	tlsConfig := &tls.Config{ServerName: opts.Host, InsecureSkipVerify: opts.Insecure}
+	dialer := &net.Dialer{Timeout: timeout}
+
+	mode := strings.ToLower(opts.TLS)
+
+	var conn net.Conn
+	var err error
+	if mode == emailTLSImplicit {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if timeout > 0 {
+		conn.SetDeadline(time.Now().Add(timeout))
+	}
+
+	c, err := smtp.NewClient(conn, opts.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	switch mode {
+	case emailTLSImplicit, emailTLSNone:
+	case emailTLSStartTLS:
+		if err := c.StartTLS(tlsConfig); err != nil {
+			c.Close()
+			return nil, err
+		}
+	case "", emailTLSAuto:
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(tlsConfig); err != nil {
+				c.Close()
+				return nil, err
+			}
+		}
+	default:
+		c.Close()
+		return nil, fmt.Errorf("email tls mode %s is not supported", opts.TLS)
+	}
+	return c, nil
+}
+
+func (e *Email) CustomSend(emailOptions EmailOptions, sendOptions EmailSendOptions) ([]byte, error) {
+
+	if utils.IsEmpty(emailOptions.Host) {
+		return nil, errors.New("email host is empty")
+	}
+	if utils.IsEmpty(sendOptions.From) {
+		return nil, errors.New("email from is empty")
+	}
+
+	var recipients []string
+	recipients = append(recipients, common.RemoveEmptyStrings(sendOptions.To)...)
+	recipients = append(recipients, common.RemoveEmptyStrings(sendOptions.Cc)...)
+	recipients = append(recipients, common.RemoveEmptyStrings(sendOptions.Bcc)...)
+	if len(recipients) == 0 {
+		return nil, errors.New("email recipients are empty")
+	}
+
+	messageID := fmt.Sprintf("<%s@%s>", uuid.New().String(), e.domain(sendOptions.From))
+	msg, err := e.buildMessage(sendOptions, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, err := e.getAuth(emailOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := e.dial(emailOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	if auth != nil {
+		if err := c.Auth(auth); err != nil {
+			return nil, err
+		}
+	}
+
+	from, err := e.address(sendOptions.From)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Mail(from); err != nil {
+		return nil, err
+	}
+
+	for _, r := range recipients {
+		to, err := e.address(r)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.Rcpt(to); err != nil {
+			return nil, err
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := c.Quit(); err != nil {
+		e.logger.Debug("Email quit err => %s", err.Error())
+	}
+
+	return common.JsonMarshal(&EmailSendResponse{
+		MessageID:  messageID,
+		From:       from,
+		Recipients: recipients,
+	})
+}
+
+func (e *Email) address(s string) (string, error) {
+
+	a, err := mail.ParseAddress(s)
+	if err != nil {
+		return "", fmt.Errorf("email address %s is invalid: %s", s, err)
+	}
+	return a.Address, nil
+}
+
+func (e *Email) Send(options EmailSendOptions) ([]byte, error) {
+	return e.CustomSend(e.options, options)
+}
+
+func NewEmail(options EmailOptions, logger common.Logger) *Email {
+
+	email := &Email{
+		options: options,
+		logger:  logger,
+	}
+	return email
+}
@@ -0,0 +1,41 @@
+package vendors
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/devopsext/utils"
+)
+
+// responseError adds messages from error response body, both {"message"} and jira {"errorMessages","errors"} are known
+func responseError(body []byte, err error) error {
+
+	var r struct {
+		Message       string            `json:"message"`
+		ErrorMessages []string          `json:"errorMessages"`
+		Errors        map[string]string `json:"errors"`
+	}
+	if json.Unmarshal(body, &r) != nil {
+		return err
+	}
+
+	var msgs []string
+	if !utils.IsEmpty(r.Message) {
+		msgs = append(msgs, r.Message)
+	}
+	msgs = append(msgs, r.ErrorMessages...)
+	keys := make([]string, 0, len(r.Errors))
+	for k := range r.Errors {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", k, r.Errors[k]))
+	}
+	if len(msgs) == 0 {
+		return err
+	}
+	return fmt.Errorf("%s: %s", err, strings.Join(msgs, "; "))
+}
@@ -1,7 +1,9 @@
 package vendors
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -11,44 +13,73 @@ import (
 	"strings"
 	"time"
 
+	"github.com/devopsext/tools/common"
 	"github.com/devopsext/utils"
 )
 
 type GrafanaRenderImageOptions struct {
-	PanelID string
-	From    string
-	To      string
-	Width   int
-	Height  int
+	PanelID       string
+	PanelIDs      []string
+	PanelTitles   []string // regexp
+	From          string
+	To            string
+	Width         int
+	Height        int
+	Concurrency   int
+	Grid          bool
+	Columns       int
+	Captions      bool
+	FullDashboard bool
 }
 
 type GrafanaGetAnnotationsOptions struct {
-	From        string
-	To          string
-	Tags        string
-	Type        string
-	Limit       int
-	AlertID     int
-	DashboardID int
-	PanelID     int
-	MatchAny    bool
+	From         string
+	To           string
+	Tags         string
+	Type         string
+	Limit        int
+	AlertID      int
+	DashboardID  int
+	DashboardUID string
+	PanelID      int
+	MatchAny     bool
 }
 
 type GrafanaCreateAnnotationOptions struct {
+	Time         string
+	TimeEnd      string
+	Tags         string
+	Text         string
+	DashboardUID string
+	PanelID      int
+}
+
+// GrafanaUpdateAnnotationOptions patches only not empty fields, set TimeEnd to close region started before
+type GrafanaUpdateAnnotationOptions struct {
+	ID      int
 	Time    string
 	TimeEnd string
 	Tags    string
 	Text    string
 }
 
+type GrafanaDeleteAnnotationOptions struct {
+	ID int
+}
+
 type GrafanaClonedDahboardOptions struct {
 	UID         string
 	Annotations []string
 	PanelIDs    []string
 	PanelTitles []string
 	PanelSeries []string
+	Vars        []string // name=value
+	VarsInline  bool
+	Datasources []string // old=new, uid or name
 	LegendRight bool
 	Arrange     bool
+	ArrangeMode string   // keep, grid or row
+	PanelSizes  []string // <panel id or title regexp>=<width>x<height>
 	Count       int
 	Width       int
 	Height      int
@@ -64,14 +95,17 @@ type GrafanaCreateDahboardOptions struct {
 }
 
 type GrafanaOptions struct {
-	URL               string
-	Timeout           int
-	Insecure          bool
-	APIKey            string
-	OrgID             string
-	DashboardUID      string
-	DashboardSlug     string
-	DashboardTimezone string
+	URL                 string
+	Timeout             int
+	Insecure            bool
+	APIKey              string
+	ServiceAccountToken string
+	User                string
+	Password            string
+	OrgID               string
+	DashboardUID        string
+	DashboardSlug       string
+	DashboardTimezone   string
 }
 
 type GrafanaDashboardTime struct {
@@ -84,6 +118,10 @@ type GrafanaDashboardAnnotations struct {
 	GraphTooltip int           `json:"graphTooltip"`
 }
 
+type GrafanaDashboardTemplating struct {
+	List []interface{} `json:"list"`
+}
+
 type GrafanaDashboard struct {
 	ID            int                         `json:"id"`
 	UID           string                      `json:"uid"`
@@ -95,6 +133,7 @@ type GrafanaDashboard struct {
 	GraphTooltip  int                         `json:"graphTooltip"`
 	Time          GrafanaDashboardTime        `json:"time"`
 	Annotations   GrafanaDashboardAnnotations `json:"annotations"`
+	Templating    *GrafanaDashboardTemplating `json:"templating,omitempty"`
 	Panels        []interface{}               `json:"panels"`
 }
 
@@ -107,10 +146,12 @@ type GrafanaBoard struct {
 }
 
 type GrafanaAnnotation struct {
-	Time    int64    `json:"time"`
-	TimeEnd int64    `json:"timeEnd"`
-	Tags    []string `json:"tags"`
-	Text    string   `json:"text"`
+	DashboardUID string   `json:"dashboardUID,omitempty"`
+	PanelID      int      `json:"panelId,omitempty"`
+	Time         int64    `json:"time"`
+	TimeEnd      int64    `json:"timeEnd"`
+	Tags         []string `json:"tags"`
+	Text         string   `json:"text"`
 }
 
 type Grafana struct {
@@ -118,28 +159,80 @@ type Grafana struct {
 	options GrafanaOptions
 }
 
+// getAuth prefers service account token, api keys are deprecated, basic auth is for older instances
 func (g *Grafana) getAuth(options GrafanaOptions) string {
+
 	auth := ""
+	if !utils.IsEmpty(options.ServiceAccountToken) {
+		auth = fmt.Sprintf("Bearer %s", options.ServiceAccountToken)
+		return auth
+	}
 	if !utils.IsEmpty(options.APIKey) {
 		auth = fmt.Sprintf("Bearer %s", options.APIKey)
+		return auth
+	}
+	if !utils.IsEmpty(options.User) {
+		userPass := fmt.Sprintf("%s:%s", options.User, options.Password)
+		auth = fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte(userPass)))
+		return auth
 	}
 	return auth
 }
 
-func (g *Grafana) CustomRenderImage(grafanaOptions GrafanaOptions, renderImageOptions GrafanaRenderImageOptions) ([]byte, error) {
+func (g *Grafana) request(grafanaOptions GrafanaOptions, method, p string, params url.Values, body []byte) ([]byte, int, error) {
+	return g.requestWithHeaders(grafanaOptions, method, p, params, nil, body)
+}
+
+func (g *Grafana) requestWithHeaders(grafanaOptions GrafanaOptions, method, p string, params url.Values, extra map[string]string, body []byte) ([]byte, int, error) {
 
 	u, err := url.Parse(grafanaOptions.URL)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	// path segments are escaped by callers, raw path keeps them from being escaped twice
+	raw := path.Join(u.EscapedPath(), p)
+	unescaped, err := url.PathUnescape(raw)
+	if err != nil {
+		return nil, 0, err
 	}
-	u.Path = path.Join(u.Path, fmt.Sprintf("/render/d-solo/%s/%s", grafanaOptions.DashboardUID, grafanaOptions.DashboardSlug))
+	u.Path, u.RawPath = unescaped, raw
+	if params != nil {
+		u.RawQuery = params.Encode()
+	}
+
+	headers := make(map[string]string)
+	headers["Content-Type"] = "application/json"
+	headers["Authorization"] = g.getAuth(grafanaOptions)
+	// tokens and api keys are rejected in other orgs, so org is sent only when it's set
+	if !utils.IsEmpty(grafanaOptions.OrgID) {
+		headers["X-Grafana-Org-Id"] = grafanaOptions.OrgID
+	}
+	for k, v := range extra {
+		headers[k] = v
+	}
+
+	b, code, err := utils.HttpRequestRawWithHeadersOutCode(g.client, method, u.String(), headers, body)
+	if err != nil {
+		return b, code, responseError(b, err)
+	}
+	return b, code, nil
+}
+
+func (g *Grafana) renderImage(grafanaOptions GrafanaOptions, renderImageOptions GrafanaRenderImageOptions, panelID string) ([]byte, error) {
+
+	kind := "d-solo"
+	if renderImageOptions.FullDashboard {
+		kind = "d"
+	}
+	p := fmt.Sprintf("/render/%s/%s/%s", kind, url.PathEscape(grafanaOptions.DashboardUID), url.PathEscape(grafanaOptions.DashboardSlug))
 
 	var params = make(url.Values)
 	if !utils.IsEmpty(grafanaOptions.OrgID) {
 		params.Add("orgId", grafanaOptions.OrgID)
 	}
-	if !utils.IsEmpty(renderImageOptions.PanelID) {
-		params.Add("panelId", renderImageOptions.PanelID)
+	if !utils.IsEmpty(panelID) {
+		params.Add("panelId", panelID)
 	}
 	if renderImageOptions.Width > 0 {
 		params.Add("width", strconv.Itoa(renderImageOptions.Width))
@@ -155,22 +248,35 @@ func (g *Grafana) CustomRenderImage(grafanaOptions GrafanaOptions, renderImageOp
 	}
 	params.Add("tz", grafanaOptions.DashboardTimezone)
 
-	u.RawQuery = params.Encode()
-	return utils.HttpGetRaw(g.client, u.String(), "", g.getAuth(grafanaOptions))
+	b, _, err := g.request(grafanaOptions, http.MethodGet, p, params, nil)
+	return b, err
 }
 
-func (g *Grafana) RenderImage(options GrafanaRenderImageOptions) ([]byte, error) {
-	return g.CustomRenderImage(g.options, options)
-}
+// CustomRenderImage renders one panel, whole dashboard or several panels composed into grid
+func (g *Grafana) CustomRenderImage(grafanaOptions GrafanaOptions, renderImageOptions GrafanaRenderImageOptions) ([]byte, error) {
 
-func (g *Grafana) CustomGetDashboards(grafanaOptions GrafanaOptions) ([]byte, error) {
-	u, err := url.Parse(grafanaOptions.URL)
+	if renderImageOptions.FullDashboard {
+		return g.renderImage(grafanaOptions, renderImageOptions, "")
+	}
+
+	if utils.IsEmpty(renderImageOptions.PanelIDs) && utils.IsEmpty(renderImageOptions.PanelTitles) && !renderImageOptions.Grid {
+		return g.renderImage(grafanaOptions, renderImageOptions, renderImageOptions.PanelID)
+	}
+
+	images, err := g.CustomRenderImages(grafanaOptions, renderImageOptions)
 	if err != nil {
 		return nil, err
 	}
+	return GrafanaComposeGrid(images, renderImageOptions.Columns, renderImageOptions.Captions)
+}
+
+func (g *Grafana) RenderImage(options GrafanaRenderImageOptions) ([]byte, error) {
+	return g.CustomRenderImage(g.options, options)
+}
 
-	u.Path = path.Join(u.Path, fmt.Sprintf("/api/dashboards/uid/%s", grafanaOptions.DashboardUID))
-	return utils.HttpGetRaw(g.client, u.String(), "", g.getAuth(grafanaOptions))
+func (g *Grafana) CustomGetDashboards(grafanaOptions GrafanaOptions) ([]byte, error) {
+	b, _, err := g.request(grafanaOptions, http.MethodGet, fmt.Sprintf("/api/dashboards/uid/%s", url.PathEscape(grafanaOptions.DashboardUID)), nil, nil)
+	return b, err
 }
 
 func (g *Grafana) GetDashboards() ([]byte, error) {
@@ -178,18 +284,13 @@ func (g *Grafana) GetDashboards() ([]byte, error) {
 }
 
 func (g Grafana) CustomCreateAnnotation(grafanaOptions GrafanaOptions, createAnnotationOptions GrafanaCreateAnnotationOptions) ([]byte, error) {
-	u, err := url.Parse(grafanaOptions.URL)
-	if err != nil {
-		return nil, err
-	}
-
-	u.Path = path.Join(u.Path, "/api/annotations")
 
 	b, err := json.Marshal(g.createAnnotation(&createAnnotationOptions))
 	if err != nil {
 		return nil, err
 	}
-	return utils.HttpPostRaw(g.client, u.String(), "application/json", g.getAuth(grafanaOptions), b)
+	b, _, err = g.request(grafanaOptions, http.MethodPost, "/api/annotations", nil, b)
+	return b, err
 }
 
 func (g *Grafana) createAnnotation(o *GrafanaCreateAnnotationOptions) *GrafanaAnnotation {
@@ -200,10 +301,12 @@ func (g *Grafana) createAnnotation(o *GrafanaCreateAnnotationOptions) *GrafanaAn
 	}
 
 	return &GrafanaAnnotation{
-		Time:    t,
-		TimeEnd: tEnd,
-		Tags:    strings.Split(o.Tags, ","),
-		Text:    o.Text,
+		DashboardUID: o.DashboardUID,
+		PanelID:      o.PanelID,
+		Time:         t,
+		TimeEnd:      tEnd,
+		Tags:         strings.Split(o.Tags, ","),
+		Text:         o.Text,
 	}
 }
 
@@ -229,12 +332,6 @@ func (g *Grafana) CreateAnnotation(options GrafanaCreateAnnotationOptions) ([]by
 }
 
 func (g *Grafana) CustomGetAnnotations(grafanaOptions GrafanaOptions, getAnnotationsOptions GrafanaGetAnnotationsOptions) ([]byte, error) {
-	u, err := url.Parse(grafanaOptions.URL)
-	if err != nil {
-		return nil, err
-	}
-
-	u.Path = path.Join(u.Path, "/api/annotations")
 
 	var params = make(url.Values)
 	for _, tag := range strings.Split(getAnnotationsOptions.Tags, ",") {
@@ -263,6 +360,9 @@ func (g *Grafana) CustomGetAnnotations(grafanaOptions GrafanaOptions, getAnnotat
 	if getAnnotationsOptions.DashboardID > 0 {
 		params.Add("dashboardId", strconv.Itoa(getAnnotationsOptions.DashboardID))
 	}
+	if !utils.IsEmpty(getAnnotationsOptions.DashboardUID) {
+		params.Add("dashboardUID", getAnnotationsOptions.DashboardUID)
+	}
 	if getAnnotationsOptions.PanelID > 0 {
 		params.Add("panelId", strconv.Itoa(getAnnotationsOptions.PanelID))
 	}
@@ -271,14 +371,83 @@ func (g *Grafana) CustomGetAnnotations(grafanaOptions GrafanaOptions, getAnnotat
 	}
 	params.Add("tz", grafanaOptions.DashboardTimezone)
 
-	u.RawQuery = params.Encode()
-	return utils.HttpGetRaw(g.client, u.String(), "", g.getAuth(grafanaOptions))
+	b, _, err := g.request(grafanaOptions, http.MethodGet, "/api/annotations", params, nil)
+	return b, err
 }
 
 func (g *Grafana) GetAnnotations(options GrafanaGetAnnotationsOptions) ([]byte, error) {
 	return g.CustomGetAnnotations(g.options, options)
 }
 
+// parseAnnotationTime doesn't fall back to now, typo would move existing annotation
+func (g *Grafana) parseAnnotationTime(ts string) (int64, error) {
+
+	if strings.EqualFold(ts, "now") {
+		return time.Now().UnixMilli(), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return 0, fmt.Errorf("grafana annotation time %s is not now or RFC3339", ts)
+	}
+	return t.UTC().UnixMilli(), nil
+}
+
+func (g *Grafana) CustomUpdateAnnotation(grafanaOptions GrafanaOptions, updateAnnotationOptions GrafanaUpdateAnnotationOptions) ([]byte, error) {
+
+	if updateAnnotationOptions.ID <= 0 {
+		return nil, errors.New("grafana annotation id is empty")
+	}
+
+	patch := make(map[string]interface{})
+	if !utils.IsEmpty(updateAnnotationOptions.Time) {
+		t, err := g.parseAnnotationTime(updateAnnotationOptions.Time)
+		if err != nil {
+			return nil, err
+		}
+		patch["time"] = t
+	}
+	if !utils.IsEmpty(updateAnnotationOptions.TimeEnd) {
+		t, err := g.parseAnnotationTime(updateAnnotationOptions.TimeEnd)
+		if err != nil {
+			return nil, err
+		}
+		patch["timeEnd"] = t
+	}
+	if !utils.IsEmpty(updateAnnotationOptions.Tags) {
+		patch["tags"] = common.RemoveEmptyStrings(strings.Split(updateAnnotationOptions.Tags, ","))
+	}
+	if !utils.IsEmpty(updateAnnotationOptions.Text) {
+		patch["text"] = updateAnnotationOptions.Text
+	}
+	if len(patch) == 0 {
+		return nil, errors.New("grafana annotation has nothing to update")
+	}
+
+	b, err := json.Marshal(patch)
+	if err != nil {
+		return nil, err
+	}
+	b, _, err = g.request(grafanaOptions, http.MethodPatch, fmt.Sprintf("/api/annotations/%d", updateAnnotationOptions.ID), nil, b)
+	return b, err
+}
+
+func (g *Grafana) UpdateAnnotation(options GrafanaUpdateAnnotationOptions) ([]byte, error) {
+	return g.CustomUpdateAnnotation(g.options, options)
+}
+
+func (g *Grafana) CustomDeleteAnnotation(grafanaOptions GrafanaOptions, deleteAnnotationOptions GrafanaDeleteAnnotationOptions) ([]byte, error) {
+
+	if deleteAnnotationOptions.ID <= 0 {
+		return nil, errors.New("grafana annotation id is empty")
+	}
+	b, _, err := g.request(grafanaOptions, http.MethodDelete, fmt.Sprintf("/api/annotations/%d", deleteAnnotationOptions.ID), nil, nil)
+	return b, err
+}
+
+func (g *Grafana) DeleteAnnotation(options GrafanaDeleteAnnotationOptions) ([]byte, error) {
+	return g.CustomDeleteAnnotation(g.options, options)
+}
+
 /*
 - make dashboard by name if it's not exists yet
 - clone the panel to new dashboard (or existed one)
@@ -410,7 +579,7 @@ func (g Grafana) findPanelsByTitle(source *[]interface{}, title string, pms *[]m
 	}
 }
 
-func (g Grafana) copyPanels(source, dest *[]interface{}, clonedDashboardOptions GrafanaClonedDahboardOptions) {
+func (g Grafana) copyPanels(source, dest *[]interface{}, clonedDashboardOptions GrafanaClonedDahboardOptions, overrides *grafanaCloneOverrides) {
 
 	if len(*source) <= 0 {
 		return
@@ -481,72 +650,27 @@ func (g Grafana) copyPanels(source, dest *[]interface{}, clonedDashboardOptions
 			}
 		}
 	}
-}
-
-func (g Grafana) arrangePanels(panels *[]interface{}, clonedDashboardOptions GrafanaClonedDahboardOptions) {
-
-	if len(*panels) <= 0 {
-		return
-	}
-
-	var xn float64 = 0
-	var y float64 = 0
-	cnt := 0
-
-	mod := clonedDashboardOptions.Count
-	if mod <= 0 {
-		mod = 3
-	}
-
-	height := clonedDashboardOptions.Height
-	if height <= 0 {
-		height = 7
-	}
 
-	width := clonedDashboardOptions.Width
-	if width <= 0 {
-		width = 6
-	}
-
-	for _, p := range *panels {
-		pm, ok := p.(map[string]interface{})
-		if ok && !g.panelIsType(pm, "row") {
-			gp, okGP := pm["gridPos"].(map[string]interface{})
-			if okGP {
-				m := cnt % clonedDashboardOptions.Count
-				if m == 0 {
-					xn = 0
-				}
-				var w float64 = float64(width)
-				gp["h"] = float64(height)
-				gp["w"] = w
-				gp["x"] = xn
-				gp["y"] = y * float64(m)
-				pm["gridPos"] = gp
-				xn = xn + w
-				cnt++
-			}
-		}
+	for _, p := range *dest {
+		overrides.apply(p)
 	}
 }
 
-func (g Grafana) CustomCreateDashboard(grafanaOptions GrafanaOptions, createDashboardOptions GrafanaCreateDahboardOptions) ([]byte, error) {
-
-	u, err := url.Parse(grafanaOptions.URL)
-	if err != nil {
-		return nil, err
-	}
-	u.Path = path.Join(u.Path, "/api/dashboards/db")
+// buildDashboard makes new board with panels cloned from other dashboard, it's not saved
+func (g Grafana) buildDashboard(grafanaOptions GrafanaOptions, createDashboardOptions GrafanaCreateDahboardOptions) (*GrafanaBoard, error) {
 
 	cloned := &GrafanaBoard{}
 	if !utils.IsEmpty(createDashboardOptions.Cloned.UID) {
 		clonedOpts := GrafanaOptions{
-			URL:          grafanaOptions.URL,
-			Timeout:      grafanaOptions.Timeout,
-			Insecure:     grafanaOptions.Insecure,
-			APIKey:       grafanaOptions.APIKey,
-			OrgID:        grafanaOptions.OrgID,
-			DashboardUID: createDashboardOptions.Cloned.UID,
+			URL:                 grafanaOptions.URL,
+			Timeout:             grafanaOptions.Timeout,
+			Insecure:            grafanaOptions.Insecure,
+			APIKey:              grafanaOptions.APIKey,
+			ServiceAccountToken: grafanaOptions.ServiceAccountToken,
+			User:                grafanaOptions.User,
+			Password:            grafanaOptions.Password,
+			OrgID:               grafanaOptions.OrgID,
+			DashboardUID:        createDashboardOptions.Cloned.UID,
 		}
 		b, err := g.CustomGetDashboards(clonedOpts)
 		if err != nil {
@@ -571,18 +695,45 @@ func (g Grafana) CustomCreateDashboard(grafanaOptions GrafanaOptions, createDash
 	req.Dashboard.Time.From = createDashboardOptions.From
 	req.Dashboard.Time.To = createDashboardOptions.To
 
+	overrides, err := newGrafanaCloneOverrides(createDashboardOptions.Cloned)
+	if err != nil {
+		return nil, err
+	}
+
 	g.copyAnnotations(&cloned.Dashboard.Annotations, &req.Dashboard.Annotations, createDashboardOptions.Cloned.Annotations)
-	g.copyPanels(&cloned.Dashboard.Panels, &req.Dashboard.Panels, createDashboardOptions.Cloned)
+	// variables are taken only to be overridden or remapped, plain clone has no templating
+	if overrides.enabled() {
+		source := cloned.Dashboard.Templating
+		if source == nil {
+			source = &GrafanaDashboardTemplating{}
+		}
+		req.Dashboard.Templating = &GrafanaDashboardTemplating{}
+		g.copyVariables(source, req.Dashboard.Templating, overrides)
+	}
+	g.copyPanels(&cloned.Dashboard.Panels, &req.Dashboard.Panels, createDashboardOptions.Cloned, overrides)
 
 	if createDashboardOptions.Cloned.Arrange {
-		g.arrangePanels(&req.Dashboard.Panels, createDashboardOptions.Cloned)
+		err = g.arrangePanels(&req.Dashboard.Panels, createDashboardOptions.Cloned)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
+}
+
+func (g Grafana) CustomCreateDashboard(grafanaOptions GrafanaOptions, createDashboardOptions GrafanaCreateDahboardOptions) ([]byte, error) {
+
+	req, err := g.buildDashboard(grafanaOptions, createDashboardOptions)
+	if err != nil {
+		return nil, err
 	}
 
 	b, err := json.Marshal(&req)
 	if err != nil {
 		return nil, err
 	}
-	return utils.HttpPostRaw(g.client, u.String(), "application/json", g.getAuth(grafanaOptions), b)
+	b, _, err = g.request(grafanaOptions, http.MethodPost, "/api/dashboards/db", nil, b)
+	return b, err
 }
 
 func (g *Grafana) CreateDashboard(options GrafanaCreateDahboardOptions) ([]byte, error) {
@@ -0,0 +1,617 @@
+package vendors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/devopsext/utils"
+)
+
+// Grafana unified alerting: provisioned alert rules and contact points, grafana managed alertmanager silences and alerts
+
+type GrafanaAlertRulesOptions struct {
+	FolderUID string
+	Group     string
+	Format    string // export only: json, yaml or hcl
+}
+
+type GrafanaAlertRulesImportOptions struct {
+	Input     string // list of rules, one rule or export file in json, content or path
+	FolderUID string // overrides rule folder
+}
+
+type GrafanaContactPointsOptions struct {
+	Name    string
+	Format  string // export only: json, yaml or hcl
+	Decrypt bool   // export only, secure settings are redacted otherwise
+}
+
+type GrafanaContactPointsImportOptions struct {
+	Input string // list of contact points, one contact point or export file in json, content or path
+}
+
+type GrafanaSilenceCreateOptions struct {
+	Matchers  []string // name=value, name!=value, name=~regex, name!~regex
+	StartsAt  string
+	Duration  string
+	CreatedBy string
+	Comment   string
+}
+
+type GrafanaSilenceListOptions struct {
+	Matchers []string
+	State    string // active, pending or expired
+}
+
+type GrafanaSilenceExpireOptions struct {
+	ID string
+}
+
+type GrafanaAlertsFiringOptions struct {
+	Matchers  []string
+	Silenced  bool
+	Inhibited bool
+}
+
+type GrafanaSilenceMatcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual bool   `json:"isEqual"`
+}
+
+type GrafanaSilence struct {
+	ID        string                   `json:"id,omitempty"`
+	Matchers  []*GrafanaSilenceMatcher `json:"matchers"`
+	StartsAt  string                   `json:"startsAt"`
+	EndsAt    string                   `json:"endsAt"`
+	CreatedBy string                   `json:"createdBy"`
+	Comment   string                   `json:"comment"`
+	Status    *struct {
+		State string `json:"state"`
+	} `json:"status,omitempty"`
+}
+
+type GrafanaAlertRuleImportResult struct {
+	UID    string `json:"uid"`
+	Title  string `json:"title"`
+	Action string `json:"action"` // created, updated or failed
+	Error  string `json:"error,omitempty"`
+}
+
+type GrafanaContactPointImportResult struct {
+	UID    string `json:"uid"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Action string `json:"action"` // created, updated or failed
+	Error  string `json:"error,omitempty"`
+}
+
+const (
+	grafanaContactPointsPath = "/api/v1/provisioning/contact-points"
+	grafanaAlertRulesPath    = "/api/v1/provisioning/alert-rules"
+	grafanaAlertmanagerPath  = "/api/alertmanager/grafana/api/v2"
+)
+
+var grafanaMatcherExpr = regexp.MustCompile(`^\s*([a-zA-Z_][\w.\-]*)\s*(=~|!~|!=|=)\s*(.*?)\s*$`)
+
+func grafanaParseMatchers(matchers []string) ([]*GrafanaSilenceMatcher, error) {
+
+	var r []*GrafanaSilenceMatcher
+	for _, m := range matchers {
+		if utils.IsEmpty(m) {
+			continue
+		}
+		parts := grafanaMatcherExpr.FindStringSubmatch(m)
+		if parts == nil {
+			return nil, fmt.Errorf("grafana matcher %s is invalid", m)
+		}
+		r = append(r, &GrafanaSilenceMatcher{
+			Name:    parts[1],
+			Value:   strings.Trim(parts[3], `"`),
+			IsRegex: strings.HasSuffix(parts[2], "~"),
+			IsEqual: !strings.HasPrefix(parts[2], "!"),
+		})
+	}
+	return r, nil
+}
+
+// grafanaMatcherFilters makes alertmanager filter params like name="value"
+func grafanaMatcherFilters(matchers []string) (url.Values, error) {
+
+	ms, err := grafanaParseMatchers(matchers)
+	if err != nil {
+		return nil, err
+	}
+	params := make(url.Values)
+	for _, m := range ms {
+		op := "="
+		switch {
+		case m.IsRegex && m.IsEqual:
+			op = "=~"
+		case m.IsRegex:
+			op = "!~"
+		case !m.IsEqual:
+			op = "!="
+		}
+		params.Add("filter", fmt.Sprintf("%s%s%q", m.Name, op, m.Value))
+	}
+	return params, nil
+}
+
+func (g *Grafana) CustomGetAlertRules(grafanaOptions GrafanaOptions, alertRulesOptions GrafanaAlertRulesOptions) ([]byte, error) {
+
+	b, _, err := g.request(grafanaOptions, http.MethodGet, grafanaAlertRulesPath, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if utils.IsEmpty(alertRulesOptions.FolderUID) && utils.IsEmpty(alertRulesOptions.Group) {
+		return b, nil
+	}
+
+	var rules []map[string]interface{}
+	if err := json.Unmarshal(b, &rules); err != nil {
+		return nil, err
+	}
+	r := []map[string]interface{}{}
+	for _, rule := range rules {
+		if !utils.IsEmpty(alertRulesOptions.FolderUID) && rule["folderUID"] != alertRulesOptions.FolderUID {
+			continue
+		}
+		if !utils.IsEmpty(alertRulesOptions.Group) && rule["ruleGroup"] != alertRulesOptions.Group {
+			continue
+		}
+		r = append(r, rule)
+	}
+	return json.Marshal(r)
+}
+
+func (g *Grafana) GetAlertRules(options GrafanaAlertRulesOptions) ([]byte, error) {
+	return g.CustomGetAlertRules(g.options, options)
+}
+
+func (g *Grafana) CustomExportAlertRules(grafanaOptions GrafanaOptions, alertRulesOptions GrafanaAlertRulesOptions) ([]byte, error) {
+
+	params := make(url.Values)
+	format := strings.ToLower(alertRulesOptions.Format)
+	if utils.IsEmpty(format) {
+		format = "json"
+	}
+	params.Add("format", format)
+	if !utils.IsEmpty(alertRulesOptions.FolderUID) {
+		params.Add("folderUid", alertRulesOptions.FolderUID)
+	}
+	if !utils.IsEmpty(alertRulesOptions.Group) {
+		params.Add("group", alertRulesOptions.Group)
+	}
+
+	b, _, err := g.request(grafanaOptions, http.MethodGet, grafanaAlertRulesPath+"/export", params, nil)
+	return b, err
+}
+
+func (g *Grafana) ExportAlertRules(options GrafanaAlertRulesOptions) ([]byte, error) {
+	return g.CustomExportAlertRules(g.options, options)
+}
+
+// grafanaAlertRulesRead accepts rules as listed, one rule or json export file with groups,
+// exported groups keep folder title which is returned per rule to be resolved into uid
+func grafanaAlertRulesRead(b []byte) ([]map[string]interface{}, []string, error) {
+
+	var rules []map[string]interface{}
+	if err := json.Unmarshal(b, &rules); err == nil {
+		return rules, make([]string, len(rules)), nil
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, nil, err
+	}
+	groups, ok := m["groups"].([]interface{})
+	if !ok {
+		return []map[string]interface{}{m}, []string{""}, nil
+	}
+
+	var folders []string
+
+	for _, v := range groups {
+		group, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		items, _ := group["rules"].([]interface{})
+		for _, item := range items {
+			rule, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			rule["ruleGroup"] = group["name"]
+			if orgID, ok := group["orgId"]; ok {
+				rule["orgID"] = orgID
+			}
+			folder, _ := group["folder"].(string)
+			rules = append(rules, rule)
+			folders = append(folders, folder)
+		}
+	}
+	return rules, folders, nil
+}
+
+// findFolderUID looks up folder by title, search finds nested folders as well
+func (g *Grafana) findFolderUID(grafanaOptions GrafanaOptions, title string) (string, error) {
+
+	params := make(url.Values)
+	params.Add("type", "dash-folder")
+	params.Add("query", title)
+	b, _, err := g.request(grafanaOptions, http.MethodGet, "/api/search", params, nil)
+	if err != nil {
+		return "", err
+	}
+
+	var items []*GrafanaSearchItem
+	if err := json.Unmarshal(b, &items); err != nil {
+		return "", err
+	}
+	var uids []string
+	for _, item := range items {
+		if item.Title == title {
+			uids = append(uids, item.UID)
+		}
+	}
+	switch len(uids) {
+	case 0:
+		return "", fmt.Errorf("grafana folder %s is not found", title)
+	case 1:
+		return uids[0], nil
+	}
+	return "", fmt.Errorf("grafana folder %s is ambiguous: %s, set folder uid", title, strings.Join(uids, ", "))
+}
+
+// CustomImportAlertRules creates missing rules and updates existing ones by uid, rules stay editable in UI
+func (g *Grafana) CustomImportAlertRules(grafanaOptions GrafanaOptions, importOptions GrafanaAlertRulesImportOptions) ([]byte, error) {
+
+	if utils.IsEmpty(importOptions.Input) {
+		return nil, errors.New("grafana alert rules input is empty")
+	}
+	b, err := utils.Content(importOptions.Input)
+	if err != nil {
+		return nil, err
+	}
+	rules, folders, err := grafanaAlertRulesRead(b)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := map[string]string{"X-Disable-Provenance": "true"}
+	folderUIDs := make(map[string]string)
+	results := []*GrafanaAlertRuleImportResult{}
+	for i, rule := range rules {
+
+		uid, _ := rule["uid"].(string)
+		title, _ := rule["title"].(string)
+		r := &GrafanaAlertRuleImportResult{UID: uid, Title: title}
+		results = append(results, r)
+
+		switch {
+		case !utils.IsEmpty(importOptions.FolderUID):
+			rule["folderUID"] = importOptions.FolderUID
+		case !utils.IsEmpty(folders[i]):
+			folderUID, ok := folderUIDs[folders[i]]
+			if !ok {
+				folderUID, err = g.findFolderUID(grafanaOptions, folders[i])
+				if err != nil {
+					r.Action, r.Error = "failed", err.Error()
+					continue
+				}
+				folderUIDs[folders[i]] = folderUID
+			}
+			rule["folderUID"] = folderUID
+		}
+
+		body, err := json.Marshal(rule)
+		if err != nil {
+			r.Action, r.Error = "failed", err.Error()
+			continue
+		}
+
+		method, p := http.MethodPost, grafanaAlertRulesPath
+		if !utils.IsEmpty(uid) {
+			_, code, err := g.request(grafanaOptions, http.MethodGet, fmt.Sprintf("%s/%s", grafanaAlertRulesPath, url.PathEscape(uid)), nil, nil)
+			switch {
+			case err == nil:
+				method, p = http.MethodPut, fmt.Sprintf("%s/%s", grafanaAlertRulesPath, url.PathEscape(uid))
+			case code != http.StatusNotFound:
+				r.Action, r.Error = "failed", err.Error()
+				continue
+			}
+		}
+
+		rb, _, err := g.requestWithHeaders(grafanaOptions, method, p, nil, headers, body)
+		if err != nil {
+			r.Action, r.Error = "failed", err.Error()
+			continue
+		}
+		r.Action = "updated"
+		if method == http.MethodPost {
+			r.Action = "created"
+			var created struct {
+				UID string `json:"uid"`
+			}
+			if json.Unmarshal(rb, &created) == nil && !utils.IsEmpty(created.UID) {
+				r.UID = created.UID
+			}
+		}
+	}
+	return json.Marshal(results)
+}
+
+func (g *Grafana) ImportAlertRules(options GrafanaAlertRulesImportOptions) ([]byte, error) {
+	return g.CustomImportAlertRules(g.options, options)
+}
+
+func (g *Grafana) CustomGetContactPoints(grafanaOptions GrafanaOptions, contactPointsOptions GrafanaContactPointsOptions) ([]byte, error) {
+
+	params := make(url.Values)
+	if !utils.IsEmpty(contactPointsOptions.Name) {
+		params.Add("name", contactPointsOptions.Name)
+	}
+	b, _, err := g.request(grafanaOptions, http.MethodGet, grafanaContactPointsPath, params, nil)
+	return b, err
+}
+
+func (g *Grafana) GetContactPoints(options GrafanaContactPointsOptions) ([]byte, error) {
+	return g.CustomGetContactPoints(g.options, options)
+}
+
+func (g *Grafana) CustomExportContactPoints(grafanaOptions GrafanaOptions, contactPointsOptions GrafanaContactPointsOptions) ([]byte, error) {
+
+	params := make(url.Values)
+	format := strings.ToLower(contactPointsOptions.Format)
+	if utils.IsEmpty(format) {
+		format = "json"
+	}
+	params.Add("format", format)
+	if !utils.IsEmpty(contactPointsOptions.Name) {
+		params.Add("name", contactPointsOptions.Name)
+	}
+	if contactPointsOptions.Decrypt {
+		params.Add("decrypt", "true")
+	}
+
+	b, _, err := g.request(grafanaOptions, http.MethodGet, grafanaContactPointsPath+"/export", params, nil)
+	return b, err
+}
+
+func (g *Grafana) ExportContactPoints(options GrafanaContactPointsOptions) ([]byte, error) {
+	return g.CustomExportContactPoints(g.options, options)
+}
+
+// grafanaContactPointsRead accepts contact points as listed, one contact point or json export file,
+// every receiver of exported contact point is imported as contact point with its name
+func grafanaContactPointsRead(b []byte) ([]map[string]interface{}, error) {
+
+	var points []map[string]interface{}
+	if err := json.Unmarshal(b, &points); err == nil {
+		return points, nil
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	exported, ok := m["contactPoints"].([]interface{})
+	if !ok {
+		return []map[string]interface{}{m}, nil
+	}
+
+	for _, v := range exported {
+		cp, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		receivers, _ := cp["receivers"].([]interface{})
+		for _, item := range receivers {
+			receiver, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			receiver["name"] = cp["name"]
+			points = append(points, receiver)
+		}
+	}
+	return points, nil
+}
+
+// CustomImportContactPoints creates missing contact points and updates existing ones by uid, they stay editable in UI
+func (g *Grafana) CustomImportContactPoints(grafanaOptions GrafanaOptions, importOptions GrafanaContactPointsImportOptions) ([]byte, error) {
+
+	if utils.IsEmpty(importOptions.Input) {
+		return nil, errors.New("grafana contact points input is empty")
+	}
+	b, err := utils.Content(importOptions.Input)
+	if err != nil {
+		return nil, err
+	}
+	points, err := grafanaContactPointsRead(b)
+	if err != nil {
+		return nil, err
+	}
+
+	// there is no get by uid, existing ones are listed
+	b, _, err = g.request(grafanaOptions, http.MethodGet, grafanaContactPointsPath, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var current []struct {
+		UID string `json:"uid"`
+	}
+	if err := json.Unmarshal(b, &current); err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool)
+	for _, c := range current {
+		existing[c.UID] = true
+	}
+
+	headers := map[string]string{"X-Disable-Provenance": "true"}
+	results := []*GrafanaContactPointImportResult{}
+	for _, point := range points {
+
+		uid, _ := point["uid"].(string)
+		name, _ := point["name"].(string)
+		typ, _ := point["type"].(string)
+		r := &GrafanaContactPointImportResult{UID: uid, Name: name, Type: typ}
+		results = append(results, r)
+
+		body, err := json.Marshal(point)
+		if err != nil {
+			r.Action, r.Error = "failed", err.Error()
+			continue
+		}
+
+		method, p := http.MethodPost, grafanaContactPointsPath
+		if !utils.IsEmpty(uid) && existing[uid] {
+			method, p = http.MethodPut, fmt.Sprintf("%s/%s", grafanaContactPointsPath, url.PathEscape(uid))
+		}
+
+		rb, _, err := g.requestWithHeaders(grafanaOptions, method, p, nil, headers, body)
+		if err != nil {
+			r.Action, r.Error = "failed", err.Error()
+			continue
+		}
+		r.Action = "updated"
+		if method == http.MethodPost {
+			r.Action = "created"
+			var created struct {
+				UID string `json:"uid"`
+			}
+			if json.Unmarshal(rb, &created) == nil && !utils.IsEmpty(created.UID) {
+				r.UID = created.UID
+			}
+		}
+	}
+	return json.Marshal(results)
+}
+
+func (g *Grafana) ImportContactPoints(options GrafanaContactPointsImportOptions) ([]byte, error) {
+	return g.CustomImportContactPoints(g.options, options)
+}
+
+func (g *Grafana) CustomCreateSilence(grafanaOptions GrafanaOptions, silenceOptions GrafanaSilenceCreateOptions) ([]byte, error) {
+
+	matchers, err := grafanaParseMatchers(silenceOptions.Matchers)
+	if err != nil {
+		return nil, err
+	}
+	if len(matchers) == 0 {
+		return nil, errors.New("grafana silence matchers are empty")
+	}
+	if utils.IsEmpty(silenceOptions.Comment) {
+		return nil, errors.New("grafana silence comment is empty")
+	}
+
+	duration, err := time.ParseDuration(silenceOptions.Duration)
+	if err != nil {
+		return nil, fmt.Errorf("grafana silence duration %s is invalid: %s", silenceOptions.Duration, err)
+	}
+
+	// typo must not start silence right away
+	startsAt := time.Now().UTC()
+	if !utils.IsEmpty(silenceOptions.StartsAt) {
+		t, err := g.parseAnnotationTime(silenceOptions.StartsAt)
+		if err != nil {
+			return nil, fmt.Errorf("grafana silence starts at %s is not now or RFC3339", silenceOptions.StartsAt)
+		}
+		startsAt = time.UnixMilli(t).UTC()
+	}
+
+	createdBy := silenceOptions.CreatedBy
+	if utils.IsEmpty(createdBy) {
+		createdBy = "tools"
+	}
+
+	silence := &GrafanaSilence{
+		Matchers:  matchers,
+		StartsAt:  startsAt.Format(time.RFC3339),
+		EndsAt:    startsAt.Add(duration).Format(time.RFC3339),
+		CreatedBy: createdBy,
+		Comment:   silenceOptions.Comment,
+	}
+	body, err := json.Marshal(silence)
+	if err != nil {
+		return nil, err
+	}
+	b, _, err := g.request(grafanaOptions, http.MethodPost, grafanaAlertmanagerPath+"/silences", nil, body)
+	return b, err
+}
+
+func (g *Grafana) CreateSilence(options GrafanaSilenceCreateOptions) ([]byte, error) {
+	return g.CustomCreateSilence(g.options, options)
+}
+
+func (g *Grafana) CustomGetSilences(grafanaOptions GrafanaOptions, silenceOptions GrafanaSilenceListOptions) ([]byte, error) {
+
+	params, err := grafanaMatcherFilters(silenceOptions.Matchers)
+	if err != nil {
+		return nil, err
+	}
+	b, _, err := g.request(grafanaOptions, http.MethodGet, grafanaAlertmanagerPath+"/silences", params, nil)
+	if err != nil {
+		return nil, err
+	}
+	if utils.IsEmpty(silenceOptions.State) {
+		return b, nil
+	}
+
+	var silences []*GrafanaSilence
+	if err := json.Unmarshal(b, &silences); err != nil {
+		return nil, err
+	}
+	r := []*GrafanaSilence{}
+	for _, s := range silences {
+		if s.Status != nil && strings.EqualFold(s.Status.State, silenceOptions.State) {
+			r = append(r, s)
+		}
+	}
+	return json.Marshal(r)
+}
+
+func (g *Grafana) GetSilences(options GrafanaSilenceListOptions) ([]byte, error) {
+	return g.CustomGetSilences(g.options, options)
+}
+
+func (g *Grafana) CustomExpireSilence(grafanaOptions GrafanaOptions, silenceOptions GrafanaSilenceExpireOptions) ([]byte, error) {
+
+	if utils.IsEmpty(silenceOptions.ID) {
+		return nil, errors.New("grafana silence id is empty")
+	}
+	b, _, err := g.request(grafanaOptions, http.MethodDelete, fmt.Sprintf("%s/silence/%s", grafanaAlertmanagerPath, url.PathEscape(silenceOptions.ID)), nil, nil)
+	return b, err
+}
+
+func (g *Grafana) ExpireSilence(options GrafanaSilenceExpireOptions) ([]byte, error) {
+	return g.CustomExpireSilence(g.options, options)
+}
+
+func (g *Grafana) CustomGetFiringAlerts(grafanaOptions GrafanaOptions, alertsOptions GrafanaAlertsFiringOptions) ([]byte, error) {
+
+	params, err := grafanaMatcherFilters(alertsOptions.Matchers)
+	if err != nil {
+		return nil, err
+	}
+	params.Add("active", "true")
+	params.Add("silenced", fmt.Sprintf("%t", alertsOptions.Silenced))
+	params.Add("inhibited", fmt.Sprintf("%t", alertsOptions.Inhibited))
+
+	b, _, err := g.request(grafanaOptions, http.MethodGet, grafanaAlertmanagerPath+"/alerts", params, nil)
+	return b, err
+}
+
+func (g *Grafana) GetFiringAlerts(options GrafanaAlertsFiringOptions) ([]byte, error) {
+	return g.CustomGetFiringAlerts(g.options, options)
+}
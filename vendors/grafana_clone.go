@@ -0,0 +1,219 @@
+package vendors
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/devopsext/utils"
+)
+
+// Template variable overrides and datasource remapping of cloned dashboards
+
+type grafanaCloneOverrides struct {
+	names       []string
+	vars        map[string]string
+	inline      bool
+	datasources map[string]string
+}
+
+// $name, ${name}, ${name:format}, [[name]] and [[name:format]]
+var grafanaVariableRef = regexp.MustCompile(`\$\{(\w+)(?::[^}]*)?\}|\[\[(\w+)(?::[^\]]*)?\]\]|\$(\w+)`)
+
+func grafanaKeyValues(items []string, what, format string) ([]string, map[string]string, error) {
+
+	var keys []string
+	m := make(map[string]string)
+	for _, item := range items {
+		if utils.IsEmpty(item) {
+			continue
+		}
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 || utils.IsEmpty(strings.TrimSpace(kv[0])) {
+			return nil, nil, fmt.Errorf("grafana cloned %s %s is invalid, expected %s", what, item, format)
+		}
+		k := strings.TrimSpace(kv[0])
+		if _, ok := m[k]; !ok {
+			keys = append(keys, k)
+		}
+		m[k] = strings.TrimSpace(kv[1])
+	}
+	return keys, m, nil
+}
+
+func newGrafanaCloneOverrides(clonedDashboardOptions GrafanaClonedDahboardOptions) (*grafanaCloneOverrides, error) {
+
+	names, vars, err := grafanaKeyValues(clonedDashboardOptions.Vars, "variable", "<name>=<value>")
+	if err != nil {
+		return nil, err
+	}
+	_, datasources, err := grafanaKeyValues(clonedDashboardOptions.Datasources, "datasource", "<old>=<new>")
+	if err != nil {
+		return nil, err
+	}
+	return &grafanaCloneOverrides{
+		names:       names,
+		vars:        vars,
+		inline:      clonedDashboardOptions.VarsInline,
+		datasources: datasources,
+	}, nil
+}
+
+// apply changes panel in place, it's used for panels, rows with their panels and variables
+func (o *grafanaCloneOverrides) apply(v interface{}) {
+
+	if len(o.datasources) > 0 {
+		o.remapDatasources(v)
+	}
+	if o.inline && len(o.vars) > 0 {
+		o.inlineVariables(v)
+	}
+}
+
+// remapDatasources replaces datasource uids and names everywhere in panel including targets
+func (o *grafanaCloneOverrides) remapDatasources(v interface{}) {
+
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, v1 := range t {
+			if k == "datasource" {
+				switch ds := v1.(type) {
+				case string:
+					if n, ok := o.datasources[ds]; ok {
+						t[k] = n
+					}
+					continue
+				case map[string]interface{}:
+					if uid, ok := ds["uid"].(string); ok {
+						if n, ok := o.datasources[uid]; ok {
+							ds["uid"] = n
+						}
+					}
+					continue
+				}
+			}
+			o.remapDatasources(v1)
+		}
+	case []interface{}:
+		for _, v1 := range t {
+			o.remapDatasources(v1)
+		}
+	}
+}
+
+// remapDatasourceVariable changes selected datasource of datasource variable, panels use it as ${ds}
+func (o *grafanaCloneOverrides) remapDatasourceVariable(v map[string]interface{}) {
+
+	remap := func(m map[string]interface{}, keys ...string) {
+		for _, k := range keys {
+			if s, ok := m[k].(string); ok {
+				if n, ok := o.datasources[s]; ok {
+					m[k] = n
+				}
+			}
+		}
+	}
+
+	remap(v, "query")
+	if current, ok := v["current"].(map[string]interface{}); ok {
+		remap(current, "text", "value")
+	}
+	if options, ok := v["options"].([]interface{}); ok {
+		for _, option := range options {
+			if om, ok := option.(map[string]interface{}); ok {
+				remap(om, "text", "value")
+			}
+		}
+	}
+}
+
+func (o *grafanaCloneOverrides) enabled() bool {
+	return len(o.vars) > 0 || len(o.datasources) > 0
+}
+
+func (o *grafanaCloneOverrides) inlineString(s string) string {
+
+	return grafanaVariableRef.ReplaceAllStringFunc(s, func(ref string) string {
+		m := grafanaVariableRef.FindStringSubmatch(ref)
+		for _, name := range m[1:] {
+			if utils.IsEmpty(name) {
+				continue
+			}
+			if value, ok := o.vars[name]; ok {
+				return value
+			}
+		}
+		return ref
+	})
+}
+
+// inlineVariables replaces overridden variable references in all strings of panel
+func (o *grafanaCloneOverrides) inlineVariables(v interface{}) interface{} {
+
+	switch t := v.(type) {
+	case string:
+		return o.inlineString(t)
+	case map[string]interface{}:
+		for k, v1 := range t {
+			t[k] = o.inlineVariables(v1)
+		}
+	case []interface{}:
+		for i, v1 := range t {
+			t[i] = o.inlineVariables(v1)
+		}
+	}
+	return v
+}
+
+func grafanaPinnedVariable(v map[string]interface{}, value string) {
+
+	option := map[string]interface{}{"text": value, "value": value, "selected": true}
+	v["type"] = "custom"
+	v["query"] = value
+	v["current"] = option
+	v["options"] = []interface{}{option}
+	v["hide"] = float64(0)
+	delete(v, "definition")
+	delete(v, "refresh")
+	delete(v, "regex")
+	delete(v, "datasource")
+}
+
+// copyVariables takes source variables, overridden ones are pinned or dropped when inlined into panels
+func (g Grafana) copyVariables(source, dest *GrafanaDashboardTemplating, overrides *grafanaCloneOverrides) {
+
+	found := make(map[string]bool)
+	for _, v := range source.List {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := m["name"].(string)
+		value, overridden := overrides.vars[name]
+		found[name] = true
+
+		if overridden && overrides.inline {
+			continue
+		}
+		overrides.apply(m)
+		if t, _ := m["type"].(string); t == "datasource" && len(overrides.datasources) > 0 {
+			overrides.remapDatasourceVariable(m)
+		}
+		if overridden {
+			grafanaPinnedVariable(m, value)
+		}
+		dest.List = append(dest.List, m)
+	}
+
+	if overrides.inline {
+		return
+	}
+	for _, name := range overrides.names {
+		if found[name] {
+			continue
+		}
+		m := map[string]interface{}{"name": name}
+		grafanaPinnedVariable(m, overrides.vars[name])
+		dest.List = append(dest.List, m)
+	}
+}
@@ -0,0 +1,443 @@
+package vendors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/devopsext/utils"
+)
+
+type GrafanaDiffOptions struct {
+	AgainstUID  string
+	AgainstFile string // dashboard json content, exported or api format
+	Format      string // text, changes
+	Ignore      []string
+}
+
+const (
+	GrafanaDiffFormatText    = "text"
+	GrafanaDiffFormatChanges = "changes"
+)
+
+// GrafanaDiffChange is change of against dashboard in compared one with old and new values, it's a change list rather than JSON patch:
+// replaced and removed paths point into against dashboard, added ones point into compared dashboard
+type GrafanaDiffChange struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Label string      `json:"label"`
+	Field string      `json:"field,omitempty"`
+	Old   interface{} `json:"old,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+type grafanaDiffPanel struct {
+	panel   map[string]interface{}
+	pointer string
+}
+
+// layout and save noise of dashboard and panels, ids are used to match panels
+var grafanaDiffIgnore = []string{"gridPos", "version", "id", "iteration", "pluginVersion"}
+
+// selected and refreshed values of variables
+var grafanaDiffVariableIgnore = []string{"current", "options"}
+
+func grafanaDiffPointer(base string, keys ...string) string {
+
+	for _, k := range keys {
+		k = strings.ReplaceAll(k, "~", "~0")
+		k = strings.ReplaceAll(k, "/", "~1")
+		base = base + "/" + k
+	}
+	return base
+}
+
+// grafanaDiffValues skips ignore keys on this level only, nested values are compared skipping nested keys
+func grafanaDiffValues(base, target interface{}, pointer, field string, ignore, nested map[string]bool, label string, changes *[]*GrafanaDiffChange) {
+
+	add := func(op string, p, f string, old, value interface{}) {
+		*changes = append(*changes, &GrafanaDiffChange{Op: op, Path: p, Value: value, Old: old, Label: label, Field: strings.TrimPrefix(f, "/")})
+	}
+
+	switch b := base.(type) {
+	case map[string]interface{}:
+		t, ok := target.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make(map[string]bool)
+		for k := range b {
+			keys[k] = true
+		}
+		for k := range t {
+			keys[k] = true
+		}
+		names := make([]string, 0, len(keys))
+		for k := range keys {
+			if !ignore[k] {
+				names = append(names, k)
+			}
+		}
+		sort.Strings(names)
+
+		for _, k := range names {
+			bv, bok := b[k]
+			tv, tok := t[k]
+			p := grafanaDiffPointer(pointer, k)
+			f := grafanaDiffPointer(field, k)
+			switch {
+			case !tok:
+				add("remove", p, f, bv, nil)
+			case !bok:
+				add("add", p, f, nil, tv)
+			default:
+				grafanaDiffValues(bv, tv, p, f, nested, nested, label, changes)
+			}
+		}
+		return
+
+	case []interface{}:
+		t, ok := target.([]interface{})
+		if !ok {
+			break
+		}
+		n := len(b)
+		if len(t) < n {
+			n = len(t)
+		}
+		for i := 0; i < n; i++ {
+			grafanaDiffValues(b[i], t[i], grafanaDiffPointer(pointer, strconv.Itoa(i)), grafanaDiffPointer(field, strconv.Itoa(i)), nested, nested, label, changes)
+		}
+		// remove from the end to keep indexes valid
+		for i := len(b) - 1; i >= n; i-- {
+			add("remove", grafanaDiffPointer(pointer, strconv.Itoa(i)), grafanaDiffPointer(field, strconv.Itoa(i)), b[i], nil)
+		}
+		for i := n; i < len(t); i++ {
+			add("add", grafanaDiffPointer(pointer, strconv.Itoa(i)), grafanaDiffPointer(field, strconv.Itoa(i)), nil, t[i])
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(base, target) {
+		add("replace", pointer, field, base, target)
+	}
+}
+
+// grafanaDiffFlattenPanels lists panels including panels of collapsed rows with their json pointers
+func grafanaDiffFlattenPanels(panels []interface{}, pointer string, list *[]*grafanaDiffPanel) {
+
+	for i, p := range panels {
+		pm, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ptr := grafanaDiffPointer(pointer, strconv.Itoa(i))
+		*list = append(*list, &grafanaDiffPanel{panel: pm, pointer: ptr})
+		if children, ok := pm["panels"].([]interface{}); ok {
+			grafanaDiffFlattenPanels(children, grafanaDiffPointer(ptr, "panels"), list)
+		}
+	}
+}
+
+func grafanaDiffPanelLabel(pm map[string]interface{}) string {
+
+	title, _ := pm["title"].(string)
+	if id, ok := pm["id"].(float64); ok {
+		return fmt.Sprintf("panel %.f %q", id, title)
+	}
+	return fmt.Sprintf("panel %q", title)
+}
+
+func grafanaDiffFind(list []*grafanaDiffPanel, pm map[string]interface{}) *grafanaDiffPanel {
+
+	for _, p := range list {
+		if reflect.ValueOf(p.panel).Pointer() == reflect.ValueOf(pm).Pointer() {
+			return p
+		}
+	}
+	return nil
+}
+
+func (g Grafana) diffPanels(base, target []interface{}, ignore map[string]bool, changes *[]*GrafanaDiffChange) {
+
+	var baseList, targetList []*grafanaDiffPanel
+	grafanaDiffFlattenPanels(base, "/panels", &baseList)
+	grafanaDiffFlattenPanels(target, "/panels", &targetList)
+
+	// row children are compared as separate panels
+	panelIgnore := map[string]bool{"panels": true}
+	for _, k := range grafanaDiffIgnore {
+		panelIgnore[k] = true
+	}
+	for k, v := range ignore {
+		panelIgnore[k] = v
+	}
+
+	matched := make(map[*grafanaDiffPanel]bool)
+	match := func(pm map[string]interface{}) *grafanaDiffPanel {
+		if pm == nil {
+			return nil
+		}
+		p := grafanaDiffFind(targetList, pm)
+		if p == nil || matched[p] {
+			return nil
+		}
+		return p
+	}
+
+	var removed []*grafanaDiffPanel
+	for _, b := range baseList {
+
+		var t *grafanaDiffPanel
+		if id, ok := b.panel["id"].(float64); ok {
+			t = match(g.findPanelByID(&target, fmt.Sprintf("%.f", id)))
+		}
+		if t == nil {
+			if title, ok := b.panel["title"].(string); ok && !utils.IsEmpty(title) {
+				pms := []map[string]interface{}{}
+				g.findPanelsByTitle(&target, fmt.Sprintf("^%s$", regexp.QuoteMeta(title)), &pms)
+				for _, pm := range pms {
+					if t = match(pm); t != nil {
+						break
+					}
+				}
+			}
+		}
+		if t == nil {
+			removed = append(removed, b)
+			continue
+		}
+		matched[t] = true
+		grafanaDiffValues(b.panel, t.panel, b.pointer, "", panelIgnore, ignore, grafanaDiffPanelLabel(t.panel), changes)
+	}
+
+	for i := len(removed) - 1; i >= 0; i-- {
+		r := removed[i]
+		// panels of removed row are removed with it
+		inRemoved := false
+		for _, o := range removed {
+			if strings.HasPrefix(r.pointer, o.pointer+"/") {
+				inRemoved = true
+			}
+		}
+		if inRemoved {
+			continue
+		}
+		*changes = append(*changes, &GrafanaDiffChange{Op: "remove", Path: r.pointer, Old: r.panel, Label: grafanaDiffPanelLabel(r.panel)})
+	}
+	for _, t := range targetList {
+		inAdded := false
+		for _, o := range targetList {
+			if !matched[o] && strings.HasPrefix(t.pointer, o.pointer+"/") {
+				inAdded = true
+			}
+		}
+		if !matched[t] && !inAdded {
+			*changes = append(*changes, &GrafanaDiffChange{Op: "add", Path: t.pointer, Value: t.panel, Label: grafanaDiffPanelLabel(t.panel)})
+		}
+	}
+}
+
+// diffNamedList compares variables or annotations matching them by name
+func (g Grafana) diffNamedList(base, target []interface{}, pointer, kind string, ignore, nested map[string]bool, changes *[]*GrafanaDiffChange) {
+
+	index := func(list []interface{}) map[string]int {
+		m := make(map[string]int)
+		for i, v := range list {
+			if vm, ok := v.(map[string]interface{}); ok {
+				if name, ok := vm["name"].(string); ok {
+					m[name] = i
+				}
+			}
+		}
+		return m
+	}
+	baseIndex := index(base)
+	targetIndex := index(target)
+
+	names := make([]string, 0, len(baseIndex))
+	for name := range baseIndex {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var removed []int
+	for _, name := range names {
+		i := baseIndex[name]
+		label := fmt.Sprintf("%s %q", kind, name)
+		j, ok := targetIndex[name]
+		if !ok {
+			removed = append(removed, i)
+			continue
+		}
+		grafanaDiffValues(base[i], target[j], grafanaDiffPointer(pointer, strconv.Itoa(i)), "", ignore, nested, label, changes)
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(removed)))
+	for _, i := range removed {
+		name := base[i].(map[string]interface{})["name"].(string)
+		*changes = append(*changes, &GrafanaDiffChange{Op: "remove", Path: grafanaDiffPointer(pointer, strconv.Itoa(i)), Old: base[i], Label: fmt.Sprintf("%s %q", kind, name)})
+	}
+	for j, v := range target {
+		vm, _ := v.(map[string]interface{})
+		name, _ := vm["name"].(string)
+		if _, ok := baseIndex[name]; !ok {
+			*changes = append(*changes, &GrafanaDiffChange{Op: "add", Path: grafanaDiffPointer(pointer, strconv.Itoa(j)), Value: v, Label: fmt.Sprintf("%s %q", kind, name)})
+		}
+	}
+}
+
+func grafanaDiffList(dashboard map[string]interface{}, key string) []interface{} {
+
+	m, _ := dashboard[key].(map[string]interface{})
+	list, _ := m["list"].([]interface{})
+	return list
+}
+
+// grafanaDiffDashboard accepts dashboard model, api response or exported file
+func grafanaDiffDashboard(b []byte) (map[string]interface{}, error) {
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	if d, ok := m["dashboard"].(map[string]interface{}); ok {
+		return d, nil
+	}
+	return m, nil
+}
+
+func (g Grafana) diffDashboards(base, target map[string]interface{}, ignore map[string]bool) []*GrafanaDiffChange {
+
+	changes := []*GrafanaDiffChange{}
+
+	top := map[string]bool{"panels": true, "templating": true, "annotations": true}
+	for _, k := range grafanaDiffIgnore {
+		top[k] = true
+	}
+	for k, v := range ignore {
+		top[k] = v
+	}
+	grafanaDiffValues(base, target, "", "", top, ignore, "dashboard", &changes)
+
+	basePanels, _ := base["panels"].([]interface{})
+	targetPanels, _ := target["panels"].([]interface{})
+	g.diffPanels(basePanels, targetPanels, ignore, &changes)
+
+	variableIgnore := make(map[string]bool)
+	for k, v := range ignore {
+		variableIgnore[k] = v
+	}
+	for _, k := range grafanaDiffVariableIgnore {
+		variableIgnore[k] = true
+	}
+	g.diffNamedList(grafanaDiffList(base, "templating"), grafanaDiffList(target, "templating"), "/templating/list", "variable", variableIgnore, ignore, &changes)
+	g.diffNamedList(grafanaDiffList(base, "annotations"), grafanaDiffList(target, "annotations"), "/annotations/list", "annotation", ignore, ignore, &changes)
+
+	return changes
+}
+
+func grafanaDiffShort(v interface{}) string {
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	s := string(b)
+	if len(s) > 120 {
+		s = s[:117] + "..."
+	}
+	return s
+}
+
+func grafanaDiffText(changes []*GrafanaDiffChange) []byte {
+
+	var buf bytes.Buffer
+	for _, c := range changes {
+		location := c.Label
+		if !utils.IsEmpty(c.Field) {
+			location = fmt.Sprintf("%s: %s", c.Label, c.Field)
+		}
+		switch c.Op {
+		case "add":
+			if utils.IsEmpty(c.Field) {
+				fmt.Fprintf(&buf, "+ %s\n", location)
+			} else {
+				fmt.Fprintf(&buf, "+ %s = %s\n", location, grafanaDiffShort(c.Value))
+			}
+		case "remove":
+			if utils.IsEmpty(c.Field) {
+				fmt.Fprintf(&buf, "- %s\n", location)
+			} else {
+				fmt.Fprintf(&buf, "- %s = %s\n", location, grafanaDiffShort(c.Old))
+			}
+		default:
+			fmt.Fprintf(&buf, "~ %s: %s -> %s\n", location, grafanaDiffShort(c.Old), grafanaDiffShort(c.Value))
+		}
+	}
+	return buf.Bytes()
+}
+
+// CustomDiffDashboards shows changes of dashboard against another dashboard or file
+func (g *Grafana) CustomDiffDashboards(grafanaOptions GrafanaOptions, diffOptions GrafanaDiffOptions) ([]byte, error) {
+
+	if utils.IsEmpty(grafanaOptions.DashboardUID) {
+		return nil, errors.New("grafana dashboard uid is empty")
+	}
+	switch diffOptions.Format {
+	case "", GrafanaDiffFormatText, GrafanaDiffFormatChanges:
+	default:
+		return nil, fmt.Errorf("grafana diff format %s is not supported", diffOptions.Format)
+	}
+
+	get := func(uid string) (map[string]interface{}, error) {
+		b, _, err := g.request(grafanaOptions, http.MethodGet, fmt.Sprintf("/api/dashboards/uid/%s", url.PathEscape(uid)), nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		return grafanaDiffDashboard(b)
+	}
+
+	target, err := get(grafanaOptions.DashboardUID)
+	if err != nil {
+		return nil, err
+	}
+
+	var base map[string]interface{}
+	switch {
+	case !utils.IsEmpty(diffOptions.AgainstUID):
+		base, err = get(diffOptions.AgainstUID)
+	case !utils.IsEmpty(diffOptions.AgainstFile):
+		base, err = grafanaDiffDashboard([]byte(diffOptions.AgainstFile))
+	default:
+		err = errors.New("grafana diff against uid or file is required")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ignore := make(map[string]bool)
+	for _, k := range diffOptions.Ignore {
+		if !utils.IsEmpty(k) {
+			ignore[k] = true
+		}
+	}
+
+	changes := g.diffDashboards(base, target, ignore)
+	if diffOptions.Format == GrafanaDiffFormatChanges {
+		return json.Marshal(changes)
+	}
+	return grafanaDiffText(changes), nil
+}
+
+func (g *Grafana) DiffDashboards(diffOptions GrafanaDiffOptions) ([]byte, error) {
+	return g.CustomDiffDashboards(g.options, diffOptions)
+}
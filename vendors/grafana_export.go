@@ -0,0 +1,687 @@
+package vendors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/devopsext/utils"
+)
+
+// Export layout: <dir>/<folder uid>/folder.json with folder metadata and <dir>/<folder uid>/<dashboard uid>.json,
+// dashboards of General folder are kept in <dir>/general
+
+type GrafanaExportOptions struct {
+	Folders []string // folder uids or titles, all if empty
+	Dir     string
+}
+
+type GrafanaImportOptions struct {
+	Dir         string
+	FolderMap   []string // source=target folder uid
+	Permissions bool
+	Message     string
+}
+
+type GrafanaPermission struct {
+	Role       string `json:"role,omitempty"`
+	TeamID     int    `json:"teamId,omitempty"`
+	Team       string `json:"team,omitempty"`
+	UserID     int    `json:"userId,omitempty"`
+	UserLogin  string `json:"userLogin,omitempty"`
+	Permission int    `json:"permission"`
+	Inherited  bool   `json:"inherited,omitempty"`
+}
+
+type GrafanaExportedFolder struct {
+	UID         string               `json:"uid"`
+	Title       string               `json:"title"`
+	ParentUID   string               `json:"parentUid,omitempty"`
+	Permissions []*GrafanaPermission `json:"permissions,omitempty"`
+}
+
+type GrafanaExportedDashboard struct {
+	Dashboard   map[string]interface{} `json:"dashboard"`
+	FolderUID   string                 `json:"folderUid,omitempty"`
+	FolderTitle string                 `json:"folderTitle,omitempty"`
+	Permissions []*GrafanaPermission   `json:"permissions,omitempty"`
+}
+
+type GrafanaSearchItem struct {
+	UID         string `json:"uid"`
+	Title       string `json:"title"`
+	Type        string `json:"type"`
+	FolderUID   string `json:"folderUid"`
+	FolderTitle string `json:"folderTitle"`
+}
+
+type GrafanaExportResult struct {
+	UID      string   `json:"uid"`
+	Title    string   `json:"title"`
+	Folder   string   `json:"folder"`
+	File     string   `json:"file"`
+	Action   string   `json:"action,omitempty"`
+	Error    string   `json:"error,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+const (
+	grafanaGeneralFolder   = "general"
+	grafanaFolderFile      = "folder.json"
+	grafanaSearchPageLimit = 1000
+)
+
+// dashboard fields which are changed on every save
+var grafanaDashboardVolatile = []string{"id", "version"}
+
+func grafanaWriteJson(file string, v interface{}) error {
+
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(b, '\n'), 0644)
+}
+
+func grafanaReadJson(file string, v interface{}) error {
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func grafanaFolderMatch(filter []string, uid, title string) bool {
+
+	if len(filter) == 0 {
+		return true
+	}
+	for _, f := range filter {
+		if strings.EqualFold(f, uid) || strings.EqualFold(f, title) {
+			return true
+		}
+	}
+	return false
+}
+
+func grafanaNormalizeDashboard(dashboard map[string]interface{}) {
+	for _, k := range grafanaDashboardVolatile {
+		delete(dashboard, k)
+	}
+}
+
+func (g *Grafana) searchDashboards(grafanaOptions GrafanaOptions) ([]*GrafanaSearchItem, error) {
+
+	var items []*GrafanaSearchItem
+	for page := 1; ; page++ {
+
+		params := make(url.Values)
+		params.Add("type", "dash-db")
+		params.Add("limit", strconv.Itoa(grafanaSearchPageLimit))
+		params.Add("page", strconv.Itoa(page))
+
+		b, _, err := g.request(grafanaOptions, http.MethodGet, "/api/search", params, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var r []*GrafanaSearchItem
+		if err := json.Unmarshal(b, &r); err != nil {
+			return nil, err
+		}
+		items = append(items, r...)
+		if len(r) < grafanaSearchPageLimit {
+			return items, nil
+		}
+	}
+}
+
+func (g *Grafana) getPermissions(grafanaOptions GrafanaOptions, p string) ([]*GrafanaPermission, error) {
+
+	b, _, err := g.request(grafanaOptions, http.MethodGet, p, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var r []*GrafanaPermission
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, err
+	}
+
+	// inherited permissions come from folder and are exported with it
+	var permissions []*GrafanaPermission
+	for _, p := range r {
+		if !p.Inherited {
+			permissions = append(permissions, p)
+		}
+	}
+	return permissions, nil
+}
+
+// grafanaIdentities keeps team and user ids of target instance, exported ids are local to source one
+type grafanaIdentities struct {
+	teams map[string]int
+	users map[string]int
+}
+
+func newGrafanaIdentities() *grafanaIdentities {
+	return &grafanaIdentities{
+		teams: make(map[string]int),
+		users: make(map[string]int),
+	}
+}
+
+func (g *Grafana) findTeamID(grafanaOptions GrafanaOptions, ids *grafanaIdentities, name string) (int, error) {
+
+	if id, ok := ids.teams[name]; ok {
+		return id, nil
+	}
+
+	params := make(url.Values)
+	params.Add("name", name)
+	b, _, err := g.request(grafanaOptions, http.MethodGet, "/api/teams/search", params, nil)
+	if err != nil {
+		return 0, err
+	}
+	var r struct {
+		Teams []struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		} `json:"teams"`
+	}
+	if err := json.Unmarshal(b, &r); err != nil {
+		return 0, err
+	}
+
+	ids.teams[name] = 0
+	for _, t := range r.Teams {
+		if t.Name == name {
+			ids.teams[name] = t.ID
+			break
+		}
+	}
+	return ids.teams[name], nil
+}
+
+func (g *Grafana) findUserID(grafanaOptions GrafanaOptions, ids *grafanaIdentities, login string) (int, error) {
+
+	if id, ok := ids.users[login]; ok {
+		return id, nil
+	}
+
+	params := make(url.Values)
+	params.Add("query", login)
+	b, _, err := g.request(grafanaOptions, http.MethodGet, "/api/org/users/lookup", params, nil)
+	if err != nil {
+		return 0, err
+	}
+	var r []struct {
+		UserID int    `json:"userId"`
+		Login  string `json:"login"`
+	}
+	if err := json.Unmarshal(b, &r); err != nil {
+		return 0, err
+	}
+
+	ids.users[login] = 0
+	for _, u := range r {
+		if u.Login == login {
+			ids.users[login] = u.UserID
+			break
+		}
+	}
+	return ids.users[login], nil
+}
+
+// setPermissions replaces permissions, teams and users are found by name and login, unknown ones are skipped and returned as warnings
+func (g *Grafana) setPermissions(grafanaOptions GrafanaOptions, ids *grafanaIdentities, p string, permissions []*GrafanaPermission) ([]string, error) {
+
+	var warnings []string
+	items := []map[string]interface{}{}
+	for _, p := range permissions {
+		item := map[string]interface{}{"permission": p.Permission}
+		switch {
+		case !utils.IsEmpty(p.Role):
+			item["role"] = p.Role
+		case !utils.IsEmpty(p.Team):
+			id, err := g.findTeamID(grafanaOptions, ids, p.Team)
+			if err != nil {
+				return nil, err
+			}
+			if id <= 0 {
+				warnings = append(warnings, fmt.Sprintf("team %s is not found, its permission is skipped", p.Team))
+				continue
+			}
+			item["teamId"] = id
+		case !utils.IsEmpty(p.UserLogin):
+			id, err := g.findUserID(grafanaOptions, ids, p.UserLogin)
+			if err != nil {
+				return nil, err
+			}
+			if id <= 0 {
+				warnings = append(warnings, fmt.Sprintf("user %s is not found, its permission is skipped", p.UserLogin))
+				continue
+			}
+			item["userId"] = id
+		default:
+			continue
+		}
+		items = append(items, item)
+	}
+
+	b, err := json.Marshal(map[string]interface{}{"items": items})
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := g.request(grafanaOptions, http.MethodPost, p, nil, b); err != nil {
+		return nil, err
+	}
+	return warnings, nil
+}
+
+func (g *Grafana) getFolders(grafanaOptions GrafanaOptions) ([]*GrafanaExportedFolder, error) {
+
+	var folders []*GrafanaExportedFolder
+	for page := 1; ; page++ {
+
+		params := make(url.Values)
+		params.Add("limit", strconv.Itoa(grafanaSearchPageLimit))
+		params.Add("page", strconv.Itoa(page))
+
+		b, _, err := g.request(grafanaOptions, http.MethodGet, "/api/folders", params, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var r []*GrafanaExportedFolder
+		if err := json.Unmarshal(b, &r); err != nil {
+			return nil, err
+		}
+		folders = append(folders, r...)
+		if len(r) < grafanaSearchPageLimit {
+			return folders, nil
+		}
+	}
+}
+
+func (g *Grafana) getFolder(grafanaOptions GrafanaOptions, uid string) (*GrafanaExportedFolder, error) {
+
+	b, _, err := g.request(grafanaOptions, http.MethodGet, fmt.Sprintf("/api/folders/%s", url.PathEscape(uid)), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var r GrafanaExportedFolder
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// exportFolders writes folder.json of every folder, nested folders are not listed by /api/folders so they are taken from dashboards and parents
+func (g *Grafana) exportFolders(grafanaOptions GrafanaOptions, exportOptions GrafanaExportOptions, items []*GrafanaSearchItem) error {
+
+	folders, err := g.getFolders(grafanaOptions)
+	if err != nil {
+		return err
+	}
+
+	known := make(map[string]bool)
+	for _, f := range folders {
+		known[f.UID] = true
+	}
+	for _, item := range items {
+		if utils.IsEmpty(item.FolderUID) || known[item.FolderUID] {
+			continue
+		}
+		known[item.FolderUID] = true
+		folders = append(folders, &GrafanaExportedFolder{UID: item.FolderUID, Title: item.FolderTitle})
+	}
+
+	// parents are appended and resolved in turn, so folders without dashboards keep the chain
+	for i := 0; i < len(folders); i++ {
+		f, err := g.getFolder(grafanaOptions, folders[i].UID)
+		if err != nil {
+			return err
+		}
+		folders[i].Title = f.Title
+		folders[i].ParentUID = f.ParentUID
+		if !utils.IsEmpty(f.ParentUID) && !known[f.ParentUID] {
+			known[f.ParentUID] = true
+			folders = append(folders, &GrafanaExportedFolder{UID: f.ParentUID})
+		}
+	}
+
+	for _, f := range folders {
+
+		if !grafanaFolderMatch(exportOptions.Folders, f.UID, f.Title) {
+			continue
+		}
+		permissions, err := g.getPermissions(grafanaOptions, fmt.Sprintf("/api/folders/%s/permissions", url.PathEscape(f.UID)))
+		if err != nil {
+			return err
+		}
+		folder := &GrafanaExportedFolder{
+			UID:         f.UID,
+			Title:       f.Title,
+			ParentUID:   f.ParentUID,
+			Permissions: permissions,
+		}
+		if err := grafanaWriteJson(filepath.Join(exportOptions.Dir, f.UID, grafanaFolderFile), folder); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *Grafana) CustomExportDashboards(grafanaOptions GrafanaOptions, exportOptions GrafanaExportOptions) ([]byte, error) {
+
+	if utils.IsEmpty(exportOptions.Dir) {
+		return nil, errors.New("grafana export dir is empty")
+	}
+
+	items, err := g.searchDashboards(grafanaOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := g.exportFolders(grafanaOptions, exportOptions, items); err != nil {
+		return nil, err
+	}
+
+	results := []*GrafanaExportResult{}
+	for _, item := range items {
+
+		folder := item.FolderUID
+		title := item.FolderTitle
+		if utils.IsEmpty(folder) {
+			folder = grafanaGeneralFolder
+			title = "General"
+		}
+		if !grafanaFolderMatch(exportOptions.Folders, folder, title) {
+			continue
+		}
+
+		b, _, err := g.request(grafanaOptions, http.MethodGet, fmt.Sprintf("/api/dashboards/uid/%s", url.PathEscape(item.UID)), nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var r struct {
+			Dashboard map[string]interface{} `json:"dashboard"`
+			Meta      struct {
+				FolderUID   string `json:"folderUid"`
+				FolderTitle string `json:"folderTitle"`
+			} `json:"meta"`
+		}
+		if err := json.Unmarshal(b, &r); err != nil {
+			return nil, err
+		}
+		grafanaNormalizeDashboard(r.Dashboard)
+
+		permissions, err := g.getPermissions(grafanaOptions, fmt.Sprintf("/api/dashboards/uid/%s/permissions", url.PathEscape(item.UID)))
+		if err != nil {
+			return nil, err
+		}
+
+		exported := &GrafanaExportedDashboard{
+			Dashboard:   r.Dashboard,
+			FolderUID:   r.Meta.FolderUID,
+			FolderTitle: r.Meta.FolderTitle,
+			Permissions: permissions,
+		}
+
+		file := filepath.Join(exportOptions.Dir, folder, fmt.Sprintf("%s.json", item.UID))
+		if err := grafanaWriteJson(file, exported); err != nil {
+			return nil, err
+		}
+		results = append(results, &GrafanaExportResult{
+			UID:    item.UID,
+			Title:  item.Title,
+			Folder: folder,
+			File:   file,
+		})
+	}
+	return json.Marshal(results)
+}
+
+func (g *Grafana) ExportDashboards(exportOptions GrafanaExportOptions) ([]byte, error) {
+	return g.CustomExportDashboards(g.options, exportOptions)
+}
+
+// importFolder creates folder with uid under its parent or updates its title and parent
+func (g *Grafana) importFolder(grafanaOptions GrafanaOptions, importOptions GrafanaImportOptions, ids *grafanaIdentities, folder *GrafanaExportedFolder, uid, parentUID string, result *GrafanaExportResult) error {
+
+	b, code, err := g.request(grafanaOptions, http.MethodGet, fmt.Sprintf("/api/folders/%s", url.PathEscape(uid)), nil, nil)
+	switch {
+	case code == http.StatusNotFound:
+		folderReq := map[string]interface{}{"uid": uid, "title": folder.Title}
+		if !utils.IsEmpty(parentUID) {
+			folderReq["parentUid"] = parentUID
+		}
+		req, err := json.Marshal(folderReq)
+		if err != nil {
+			return err
+		}
+		if _, _, err := g.request(grafanaOptions, http.MethodPost, "/api/folders", nil, req); err != nil {
+			return err
+		}
+		result.Action = "created"
+	case err != nil:
+		return err
+	default:
+		var current struct {
+			Title     string `json:"title"`
+			ParentUID string `json:"parentUid"`
+			Version   int    `json:"version"`
+		}
+		if err := json.Unmarshal(b, &current); err != nil {
+			return err
+		}
+		result.Action = "unchanged"
+		if current.Title != folder.Title {
+			req, err := json.Marshal(map[string]interface{}{"title": folder.Title, "version": current.Version, "overwrite": true})
+			if err != nil {
+				return err
+			}
+			if _, _, err := g.request(grafanaOptions, http.MethodPut, fmt.Sprintf("/api/folders/%s", url.PathEscape(uid)), nil, req); err != nil {
+				return err
+			}
+			result.Action = "updated"
+		}
+		if current.ParentUID != parentUID {
+			req, err := json.Marshal(map[string]interface{}{"parentUid": parentUID})
+			if err != nil {
+				return err
+			}
+			if _, _, err := g.request(grafanaOptions, http.MethodPost, fmt.Sprintf("/api/folders/%s/move", url.PathEscape(uid)), nil, req); err != nil {
+				return err
+			}
+			result.Action = "updated"
+		}
+	}
+
+	if importOptions.Permissions && folder.Permissions != nil {
+		result.Warnings, err = g.setPermissions(grafanaOptions, ids, fmt.Sprintf("/api/folders/%s/permissions", url.PathEscape(uid)), folder.Permissions)
+		return err
+	}
+	return nil
+}
+
+func (g *Grafana) importDashboard(grafanaOptions GrafanaOptions, importOptions GrafanaImportOptions, ids *grafanaIdentities, file, folderUID string, result *GrafanaExportResult) error {
+
+	var exported GrafanaExportedDashboard
+	if err := grafanaReadJson(file, &exported); err != nil {
+		return err
+	}
+	if exported.Dashboard == nil {
+		return fmt.Errorf("grafana dashboard is not found in %s", file)
+	}
+	grafanaNormalizeDashboard(exported.Dashboard)
+
+	result.UID, _ = exported.Dashboard["uid"].(string)
+	result.Title, _ = exported.Dashboard["title"].(string)
+
+	req, err := json.Marshal(map[string]interface{}{
+		"dashboard": exported.Dashboard,
+		"folderUid": folderUID,
+		"overwrite": true,
+		"message":   importOptions.Message,
+	})
+	if err != nil {
+		return err
+	}
+	b, _, err := g.request(grafanaOptions, http.MethodPost, "/api/dashboards/db", nil, req)
+	if err != nil {
+		return err
+	}
+
+	var r struct {
+		UID    string `json:"uid"`
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(b, &r); err != nil {
+		return err
+	}
+	result.Action = r.Status
+	if !utils.IsEmpty(r.UID) {
+		result.UID = r.UID
+	}
+
+	if importOptions.Permissions && exported.Permissions != nil {
+		result.Warnings, err = g.setPermissions(grafanaOptions, ids, fmt.Sprintf("/api/dashboards/uid/%s/permissions", url.PathEscape(result.UID)), exported.Permissions)
+		return err
+	}
+	return nil
+}
+
+// CustomImportDashboards upserts folders and dashboards from export dir, dashboard errors don't stop import
+func (g *Grafana) CustomImportDashboards(grafanaOptions GrafanaOptions, importOptions GrafanaImportOptions) ([]byte, error) {
+
+	if utils.IsEmpty(importOptions.Dir) {
+		return nil, errors.New("grafana import dir is empty")
+	}
+
+	folderMap := make(map[string]string)
+	for _, m := range importOptions.FolderMap {
+		kv := strings.SplitN(m, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("grafana folder map %s should be source=target", m)
+		}
+		folderMap[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+
+	entries, err := os.ReadDir(importOptions.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	targetUID := func(source string) string {
+		if m, ok := folderMap[source]; ok {
+			return m
+		}
+		return source
+	}
+
+	var sources []string
+	folders := make(map[string]*GrafanaExportedFolder)
+	for _, entry := range entries {
+
+		if !entry.IsDir() {
+			continue
+		}
+		source := entry.Name()
+		folder := &GrafanaExportedFolder{UID: source, Title: source}
+		file := filepath.Join(importOptions.Dir, source, grafanaFolderFile)
+		if utils.FileExists(file) {
+			if err := grafanaReadJson(file, folder); err != nil {
+				return nil, err
+			}
+		}
+		sources = append(sources, source)
+		folders[source] = folder
+	}
+
+	ids := newGrafanaIdentities()
+	results := []*GrafanaExportResult{}
+
+	// parents are imported before their children
+	imported := make(map[string]bool)
+	var ensureFolder func(source string) error
+	ensureFolder = func(source string) error {
+
+		folder, ok := folders[source]
+		target := targetUID(source)
+		if !ok || imported[source] || target == grafanaGeneralFolder {
+			return nil
+		}
+		imported[source] = true
+
+		parentUID := ""
+		if !utils.IsEmpty(folder.ParentUID) {
+			if err := ensureFolder(folder.ParentUID); err != nil {
+				return err
+			}
+			parentUID = targetUID(folder.ParentUID)
+			if parentUID == grafanaGeneralFolder {
+				parentUID = ""
+			}
+		}
+
+		result := &GrafanaExportResult{UID: target, Title: folder.Title, Folder: target, File: filepath.Join(importOptions.Dir, source, grafanaFolderFile)}
+		if err := g.importFolder(grafanaOptions, importOptions, ids, folder, target, parentUID, result); err != nil {
+			return err
+		}
+		results = append(results, result)
+		return nil
+	}
+
+	for _, source := range sources {
+		if err := ensureFolder(source); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, source := range sources {
+
+		target := targetUID(source)
+		folderUID := ""
+		if target != grafanaGeneralFolder {
+			folderUID = target
+		}
+
+		files, err := filepath.Glob(filepath.Join(importOptions.Dir, source, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+
+		for _, file := range files {
+
+			if filepath.Base(file) == grafanaFolderFile {
+				continue
+			}
+			result := &GrafanaExportResult{Folder: target, File: file}
+			if err := g.importDashboard(grafanaOptions, importOptions, ids, file, folderUID, result); err != nil {
+				result.Action = "failed"
+				result.Error = err.Error()
+			}
+			results = append(results, result)
+		}
+	}
+	return json.Marshal(results)
+}
+
+func (g *Grafana) ImportDashboards(importOptions GrafanaImportOptions) ([]byte, error) {
+	return g.CustomImportDashboards(g.options, importOptions)
+}
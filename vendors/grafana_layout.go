@@ -0,0 +1,232 @@
+package vendors
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/devopsext/utils"
+)
+
+// Layout of cloned panels on 24 units wide grafana grid, rows start a new line and keep their collapsed panels
+
+const (
+	GrafanaArrangeKeep = "keep" // original panel sizes
+	GrafanaArrangeGrid = "grid" // same size panels in columns
+	GrafanaArrangeRow  = "row"  // one full width panel per line
+
+	grafanaGridWidth = 24
+)
+
+type grafanaPanelSize struct {
+	key    string
+	title  *regexp.Regexp
+	width  int
+	height int
+}
+
+type grafanaLayout struct {
+	mode    string
+	columns int
+	width   int
+	height  int
+	sizes   []*grafanaPanelSize
+}
+
+// grafanaShelf places panels line by line, line height is the highest panel in it
+type grafanaShelf struct {
+	x      int
+	y      int
+	height int
+	count  int
+}
+
+func grafanaParsePanelSizes(sizes []string) ([]*grafanaPanelSize, error) {
+
+	var r []*grafanaPanelSize
+	for _, s := range sizes {
+		if utils.IsEmpty(s) {
+			continue
+		}
+		idx := strings.LastIndex(s, "=")
+		if idx <= 0 {
+			return nil, fmt.Errorf("grafana panel size %s is invalid, expected <id or title>=<width>x<height>", s)
+		}
+		ps := &grafanaPanelSize{key: strings.TrimSpace(s[:idx])}
+		if _, err := fmt.Sscanf(strings.TrimSpace(s[idx+1:]), "%dx%d", &ps.width, &ps.height); err != nil {
+			return nil, fmt.Errorf("grafana panel size %s is invalid: %s", s, err)
+		}
+		if ps.width <= 0 || ps.width > grafanaGridWidth || ps.height <= 0 {
+			return nil, fmt.Errorf("grafana panel size %s is out of grid", s)
+		}
+		// numeric key is panel id, anything else is title regexp
+		if _, err := strconv.Atoi(ps.key); err != nil {
+			re, err := regexp.Compile(ps.key)
+			if err != nil {
+				return nil, err
+			}
+			ps.title = re
+		}
+		r = append(r, ps)
+	}
+	return r, nil
+}
+
+func newGrafanaLayout(clonedDashboardOptions GrafanaClonedDahboardOptions) (*grafanaLayout, error) {
+
+	mode := strings.ToLower(clonedDashboardOptions.ArrangeMode)
+	if utils.IsEmpty(mode) {
+		mode = GrafanaArrangeGrid
+	}
+	switch mode {
+	case GrafanaArrangeKeep, GrafanaArrangeGrid, GrafanaArrangeRow:
+	default:
+		return nil, fmt.Errorf("grafana arrange mode %s is not supported", clonedDashboardOptions.ArrangeMode)
+	}
+
+	sizes, err := grafanaParsePanelSizes(clonedDashboardOptions.PanelSizes)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &grafanaLayout{
+		mode:    mode,
+		columns: clonedDashboardOptions.Count,
+		width:   clonedDashboardOptions.Width,
+		height:  clonedDashboardOptions.Height,
+		sizes:   sizes,
+	}
+	if l.columns <= 0 {
+		l.columns = 3
+	}
+	if l.columns > grafanaGridWidth {
+		l.columns = grafanaGridWidth
+	}
+	if l.width <= 0 || l.width > grafanaGridWidth {
+		l.width = grafanaGridWidth / l.columns
+	}
+	if l.height <= 0 {
+		l.height = 7
+	}
+	return l, nil
+}
+
+func grafanaGridPosInt(gp map[string]interface{}, key string) int {
+
+	v, ok := gp[key].(float64)
+	if !ok {
+		return 0
+	}
+	return int(v)
+}
+
+func (l *grafanaLayout) size(pm, gp map[string]interface{}) (int, int) {
+
+	w, h := l.width, l.height
+	switch l.mode {
+	case GrafanaArrangeKeep:
+		if v := grafanaGridPosInt(gp, "w"); v > 0 {
+			w = v
+		}
+		if v := grafanaGridPosInt(gp, "h"); v > 0 {
+			h = v
+		}
+	case GrafanaArrangeRow:
+		w = grafanaGridWidth
+	}
+
+	id := ""
+	if v, ok := pm["id"].(float64); ok {
+		id = fmt.Sprintf("%.f", v)
+	}
+	title, _ := pm["title"].(string)
+
+	for _, ps := range l.sizes {
+		if (ps.title == nil && ps.key == id) || (ps.title != nil && ps.title.MatchString(title)) {
+			w, h = ps.width, ps.height
+			break
+		}
+	}
+
+	if w > grafanaGridWidth {
+		w = grafanaGridWidth
+	}
+	return w, h
+}
+
+func (l *grafanaLayout) place(s *grafanaShelf, pm map[string]interface{}) {
+
+	gp, ok := pm["gridPos"].(map[string]interface{})
+	if !ok {
+		gp = make(map[string]interface{})
+	}
+	w, h := l.size(pm, gp)
+
+	full := s.x+w > grafanaGridWidth
+	if l.mode == GrafanaArrangeGrid && s.count >= l.columns {
+		full = true
+	}
+	if s.count > 0 && full {
+		s.y = s.next()
+		s.x, s.height, s.count = 0, 0, 0
+	}
+
+	gp["x"] = float64(s.x)
+	gp["y"] = float64(s.y)
+	gp["w"] = float64(w)
+	gp["h"] = float64(h)
+	pm["gridPos"] = gp
+
+	s.x += w
+	s.count++
+	if h > s.height {
+		s.height = h
+	}
+}
+
+// next returns y below current line
+func (s *grafanaShelf) next() int {
+	return s.y + s.height
+}
+
+func (g Grafana) arrangePanels(panels *[]interface{}, clonedDashboardOptions GrafanaClonedDahboardOptions) error {
+
+	if len(*panels) <= 0 {
+		return nil
+	}
+
+	l, err := newGrafanaLayout(clonedDashboardOptions)
+	if err != nil {
+		return err
+	}
+
+	shelf := &grafanaShelf{}
+	for _, p := range *panels {
+		pm, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if !g.panelIsType(pm, "row") {
+			l.place(shelf, pm)
+			continue
+		}
+
+		y := shelf.next()
+		pm["gridPos"] = map[string]interface{}{"x": float64(0), "y": float64(y), "w": float64(grafanaGridWidth), "h": float64(1)}
+		shelf = &grafanaShelf{y: y + 1}
+
+		// collapsed row keeps its panels inside, they are placed below the row as if it was expanded
+		pnls, okPnls := pm["panels"].([]interface{})
+		if !okPnls {
+			continue
+		}
+		inner := &grafanaShelf{y: y + 1}
+		for _, p1 := range pnls {
+			if pm1, ok := p1.(map[string]interface{}); ok {
+				l.place(inner, pm1)
+			}
+		}
+	}
+	return nil
+}
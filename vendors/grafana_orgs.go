@@ -0,0 +1,137 @@
+package vendors
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/devopsext/utils"
+)
+
+// Organizations the credentials can access, operations run per org through X-Grafana-Org-Id header
+
+type GrafanaOrgsOptions struct {
+	Orgs []string // ids or names, all if empty
+}
+
+type GrafanaOrg struct {
+	OrgID int    `json:"orgId"`
+	Name  string `json:"name"`
+	Role  string `json:"role,omitempty"`
+}
+
+type GrafanaOrgResult struct {
+	OrgID  int             `json:"orgId"`
+	Name   string          `json:"name"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// getOrgs lists orgs of user, tokens and api keys belong to one org and are denied user orgs,
+// so current org is used only for them and other failures are returned
+func (g *Grafana) getOrgs(grafanaOptions GrafanaOptions) ([]*GrafanaOrg, error) {
+
+	var orgs []*GrafanaOrg
+	b, code, err := g.request(grafanaOptions, http.MethodGet, "/api/user/orgs", nil, nil)
+	if err == nil {
+		if err := json.Unmarshal(b, &orgs); err != nil {
+			return nil, err
+		}
+		return orgs, nil
+	}
+	if code != http.StatusUnauthorized && code != http.StatusForbidden && code != http.StatusNotFound {
+		return nil, err
+	}
+
+	b, _, err = g.request(grafanaOptions, http.MethodGet, "/api/org", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var org struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(b, &org); err != nil {
+		return nil, err
+	}
+	return []*GrafanaOrg{{OrgID: org.ID, Name: org.Name}}, nil
+}
+
+func (g *Grafana) filterOrgs(orgs []*GrafanaOrg, orgsOptions GrafanaOrgsOptions) ([]*GrafanaOrg, error) {
+
+	if utils.IsEmpty(orgsOptions.Orgs) {
+		return orgs, nil
+	}
+
+	var r []*GrafanaOrg
+	for _, o := range orgsOptions.Orgs {
+		var found *GrafanaOrg
+		for _, org := range orgs {
+			if strconv.Itoa(org.OrgID) == o || org.Name == o {
+				found = org
+				break
+			}
+		}
+		if found == nil {
+			return nil, fmt.Errorf("grafana org %s is not found", o)
+		}
+		r = append(r, found)
+	}
+	return r, nil
+}
+
+func (g *Grafana) CustomGetOrgs(grafanaOptions GrafanaOptions, orgsOptions GrafanaOrgsOptions) ([]byte, error) {
+
+	orgs, err := g.getOrgs(grafanaOptions)
+	if err != nil {
+		return nil, err
+	}
+	orgs, err = g.filterOrgs(orgs, orgsOptions)
+	if err != nil {
+		return nil, err
+	}
+	if orgs == nil {
+		orgs = []*GrafanaOrg{}
+	}
+	return json.Marshal(orgs)
+}
+
+func (g *Grafana) GetOrgs(options GrafanaOrgsOptions) ([]byte, error) {
+	return g.CustomGetOrgs(g.options, options)
+}
+
+// CustomForEachOrg runs operation with org id of every org, failure in one org doesn't stop others
+func (g *Grafana) CustomForEachOrg(grafanaOptions GrafanaOptions, orgsOptions GrafanaOrgsOptions, operation func(org *GrafanaOrg, grafanaOptions GrafanaOptions) ([]byte, error)) ([]byte, error) {
+
+	orgs, err := g.getOrgs(grafanaOptions)
+	if err != nil {
+		return nil, err
+	}
+	orgs, err = g.filterOrgs(orgs, orgsOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []*GrafanaOrgResult{}
+	for _, org := range orgs {
+		orgOpts := grafanaOptions
+		orgOpts.OrgID = strconv.Itoa(org.OrgID)
+
+		r := &GrafanaOrgResult{OrgID: org.OrgID, Name: org.Name}
+		b, err := operation(org, orgOpts)
+		if err != nil {
+			r.Error = err.Error()
+		} else if json.Valid(b) {
+			r.Result = b
+		} else if len(b) > 0 {
+			r.Result, _ = json.Marshal(string(b))
+		}
+		results = append(results, r)
+	}
+	return json.Marshal(results)
+}
+
+func (g *Grafana) ForEachOrg(options GrafanaOrgsOptions, operation func(org *GrafanaOrg, grafanaOptions GrafanaOptions) ([]byte, error)) ([]byte, error) {
+	return g.CustomForEachOrg(g.options, options, operation)
+}
@@ -0,0 +1,250 @@
+package vendors
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/devopsext/tools/common"
+	"github.com/devopsext/utils"
+)
+
+// Query any datasource through grafana /api/ds/query, data frames are flattened into rows
+
+type GrafanaQueryOptions struct {
+	DatasourceUID string
+	Expr          string
+	Query         string // json map of additional query fields, content or path
+	From          string
+	To            string
+	Format        string // json or csv
+	MaxDataPoints int
+}
+
+type GrafanaQueryField struct {
+	Name   string            `json:"name"`
+	Type   string            `json:"type"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+type GrafanaQueryFrame struct {
+	Schema struct {
+		Name   string               `json:"name"`
+		RefID  string               `json:"refId"`
+		Fields []*GrafanaQueryField `json:"fields"`
+	} `json:"schema"`
+	Data struct {
+		Values [][]interface{} `json:"values"`
+	} `json:"data"`
+}
+
+type GrafanaQueryResponse struct {
+	Results map[string]struct {
+		Status int                  `json:"status"`
+		Error  string               `json:"error"`
+		Frames []*GrafanaQueryFrame `json:"frames"`
+	} `json:"results"`
+}
+
+type grafanaQueryTable struct {
+	columns []string
+	known   map[string]bool
+	rows    []map[string]interface{}
+}
+
+func (t *grafanaQueryTable) column(name string) {
+	if !t.known[name] {
+		t.known[name] = true
+		t.columns = append(t.columns, name)
+	}
+}
+
+func grafanaQueryLabelKeys(labels map[string]string) []string {
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func grafanaQueryLabels(labels map[string]string) string {
+
+	var pairs []string
+	for _, k := range grafanaQueryLabelKeys(labels) {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", k, labels[k]))
+	}
+	return fmt.Sprintf("{%s}", strings.Join(pairs, ","))
+}
+
+// add flattens frame, labels of the only labeled field become columns otherwise they are part of field name
+func (t *grafanaQueryTable) add(frame *GrafanaQueryFrame) {
+
+	fields := frame.Schema.Fields
+	labeled := 0
+	for _, f := range fields {
+		if len(f.Labels) > 0 {
+			labeled++
+		}
+	}
+
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.Name
+		if len(f.Labels) > 0 && labeled > 1 {
+			names[i] = f.Name + grafanaQueryLabels(f.Labels)
+		}
+	}
+
+	count := 0
+	if len(frame.Data.Values) > 0 {
+		count = len(frame.Data.Values[0])
+	}
+
+	for r := 0; r < count; r++ {
+		row := make(map[string]interface{})
+		if !utils.IsEmpty(frame.Schema.Name) {
+			t.column("frame")
+			row["frame"] = frame.Schema.Name
+		}
+		for i, f := range fields {
+			if i >= len(frame.Data.Values) || r >= len(frame.Data.Values[i]) {
+				continue
+			}
+			v := frame.Data.Values[i][r]
+			if ms, ok := v.(float64); ok && f.Type == "time" {
+				v = time.UnixMilli(int64(ms)).UTC().Format(time.RFC3339Nano)
+			}
+			t.column(names[i])
+			row[names[i]] = v
+
+			if labeled == 1 {
+				for _, k := range grafanaQueryLabelKeys(f.Labels) {
+					t.column(k)
+					row[k] = f.Labels[k]
+				}
+			}
+		}
+		t.rows = append(t.rows, row)
+	}
+}
+
+func (t *grafanaQueryTable) csv() ([]byte, error) {
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(t.columns); err != nil {
+		return nil, err
+	}
+	for _, row := range t.rows {
+		record := make([]string, len(t.columns))
+		for i, c := range t.columns {
+			if v, ok := row[c]; ok && v != nil {
+				record[i] = fmt.Sprintf("%v", v)
+			}
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func (g *Grafana) CustomQuery(grafanaOptions GrafanaOptions, queryOptions GrafanaQueryOptions) ([]byte, error) {
+
+	if utils.IsEmpty(queryOptions.DatasourceUID) {
+		return nil, errors.New("grafana query datasource uid is empty")
+	}
+
+	query := make(map[string]interface{})
+	if !utils.IsEmpty(queryOptions.Query) {
+		q, err := common.ReadAndMarshal(queryOptions.Query)
+		if err != nil {
+			return nil, err
+		}
+		if q != nil {
+			query = q
+		}
+	}
+	query["refId"] = "A"
+	query["datasource"] = map[string]interface{}{"uid": queryOptions.DatasourceUID}
+	if !utils.IsEmpty(queryOptions.Expr) {
+		query["expr"] = queryOptions.Expr
+		if _, ok := query["query"]; !ok {
+			query["query"] = queryOptions.Expr
+		}
+	}
+	if queryOptions.MaxDataPoints > 0 {
+		query["maxDataPoints"] = queryOptions.MaxDataPoints
+	}
+
+	from := queryOptions.From
+	if utils.IsEmpty(from) {
+		from = "now-1h"
+	}
+	to := queryOptions.To
+	if utils.IsEmpty(to) {
+		to = "now"
+	}
+
+	req := map[string]interface{}{
+		"queries": []interface{}{query},
+		"from":    g.toRFC3339NanoStr(from),
+		"to":      g.toRFC3339NanoStr(to),
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// failed queries come with bad request status and error inside results
+	b, _, reqErr := g.request(grafanaOptions, http.MethodPost, "/api/ds/query", nil, body)
+
+	var r GrafanaQueryResponse
+	if err := json.Unmarshal(b, &r); err != nil {
+		if reqErr != nil {
+			return nil, reqErr
+		}
+		return nil, err
+	}
+
+	refIDs := make([]string, 0, len(r.Results))
+	for refID := range r.Results {
+		refIDs = append(refIDs, refID)
+	}
+	sort.Strings(refIDs)
+
+	t := &grafanaQueryTable{known: make(map[string]bool)}
+	for _, refID := range refIDs {
+		result := r.Results[refID]
+		if !utils.IsEmpty(result.Error) {
+			return nil, fmt.Errorf("grafana query %s: %s", refID, result.Error)
+		}
+		for _, frame := range result.Frames {
+			t.add(frame)
+		}
+	}
+	if reqErr != nil {
+		return nil, reqErr
+	}
+
+	if strings.EqualFold(queryOptions.Format, "csv") {
+		return t.csv()
+	}
+	if t.rows == nil {
+		t.rows = []map[string]interface{}{}
+	}
+	return json.Marshal(t.rows)
+}
+
+func (g *Grafana) Query(options GrafanaQueryOptions) ([]byte, error) {
+	return g.CustomQuery(g.options, options)
+}
@@ -0,0 +1,235 @@
+package vendors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/devopsext/utils"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+type GrafanaRenderedImage struct {
+	PanelID string
+	Title   string
+	Image   []byte
+}
+
+const (
+	grafanaGridCaptionHeight = 22
+	grafanaGridPadding       = 6
+)
+
+type grafanaRenderPanel struct {
+	id    string
+	title string
+}
+
+// renderPanels resolves panel ids and titles into list of panels, titles are taken from dashboard
+func (g *Grafana) renderPanels(grafanaOptions GrafanaOptions, renderImageOptions GrafanaRenderImageOptions) ([]*grafanaRenderPanel, error) {
+
+	var ids []string
+	if !utils.IsEmpty(renderImageOptions.PanelID) {
+		ids = append(ids, renderImageOptions.PanelID)
+	}
+	for _, id := range renderImageOptions.PanelIDs {
+		if !utils.IsEmpty(id) {
+			ids = append(ids, id)
+		}
+	}
+
+	b, _, err := g.request(grafanaOptions, http.MethodGet, fmt.Sprintf("/api/dashboards/uid/%s", url.PathEscape(grafanaOptions.DashboardUID)), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	board := &GrafanaBoard{}
+	if err := json.Unmarshal(b, board); err != nil {
+		return nil, err
+	}
+
+	var panels []*grafanaRenderPanel
+	seen := make(map[string]bool)
+	add := func(pm map[string]interface{}, id string) {
+		if seen[id] {
+			return
+		}
+		seen[id] = true
+		title := ""
+		if pm != nil {
+			title, _ = pm["title"].(string)
+		}
+		panels = append(panels, &grafanaRenderPanel{id: id, title: title})
+	}
+
+	for _, id := range ids {
+		add(g.findPanelByID(&board.Dashboard.Panels, id), id)
+	}
+
+	for _, title := range renderImageOptions.PanelTitles {
+		if utils.IsEmpty(title) {
+			continue
+		}
+		pms := []map[string]interface{}{}
+		g.findPanelsByTitle(&board.Dashboard.Panels, title, &pms)
+		for _, pm := range pms {
+			id, ok := pm["id"].(float64)
+			if !ok || g.panelIsType(pm, "row") {
+				continue
+			}
+			add(pm, fmt.Sprintf("%.f", id))
+		}
+	}
+
+	if len(panels) == 0 {
+		return nil, errors.New("grafana panels to render are not found")
+	}
+	return panels, nil
+}
+
+// CustomRenderImages renders panels in parallel keeping their order
+func (g *Grafana) CustomRenderImages(grafanaOptions GrafanaOptions, renderImageOptions GrafanaRenderImageOptions) ([]*GrafanaRenderedImage, error) {
+
+	panels, err := g.renderPanels(grafanaOptions, renderImageOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	concurrency := renderImageOptions.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	images := make([]*GrafanaRenderedImage, len(panels))
+	errs := make([]error, len(panels))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, p := range panels {
+		wg.Add(1)
+		go func(i int, p *grafanaRenderPanel) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			b, err := g.renderImage(grafanaOptions, renderImageOptions, p.id)
+			if err != nil {
+				errs[i] = fmt.Errorf("grafana panel %s: %s", p.id, err)
+				return
+			}
+			images[i] = &GrafanaRenderedImage{PanelID: p.id, Title: p.title, Image: b}
+		}(i, p)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return images, nil
+}
+
+func (g *Grafana) RenderImages(options GrafanaRenderImageOptions) ([]*GrafanaRenderedImage, error) {
+	return g.CustomRenderImages(g.options, options)
+}
+
+func grafanaGridCaption(dst draw.Image, x, y, width int, text string) {
+
+	face := basicfont.Face7x13
+	max := (width - 2*grafanaGridPadding) / face.Advance
+	if max <= 0 {
+		return
+	}
+	// basic font has ascii glyphs only, text is cut by runes to keep it valid
+	runes := []rune(text)
+	for i, r := range runes {
+		if r < 0x20 || r > 0x7e {
+			runes[i] = '?'
+		}
+	}
+	if len(runes) > max {
+		if max > 3 {
+			runes = append(runes[:max-3], []rune("...")...)
+		} else {
+			runes = runes[:max]
+		}
+	}
+	text = string(runes)
+
+	d := &font.Drawer{
+		Dst:  dst,
+		Src:  image.NewUniform(color.Black),
+		Face: face,
+		Dot:  fixed.P(x+grafanaGridPadding, y+(grafanaGridCaptionHeight+face.Ascent)/2),
+	}
+	d.DrawString(text)
+}
+
+// GrafanaComposeGrid composes png images into one png grid with optional captions
+func GrafanaComposeGrid(images []*GrafanaRenderedImage, columns int, captions bool) ([]byte, error) {
+
+	if len(images) == 0 {
+		return nil, errors.New("grafana no images to compose")
+	}
+	if columns <= 0 {
+		columns = 2
+	}
+	if columns > len(images) {
+		columns = len(images)
+	}
+
+	decoded := make([]image.Image, len(images))
+	cellW, cellH := 0, 0
+	for i, img := range images {
+		d, err := png.Decode(bytes.NewReader(img.Image))
+		if err != nil {
+			return nil, fmt.Errorf("grafana panel %s image: %s", img.PanelID, err)
+		}
+		decoded[i] = d
+		if d.Bounds().Dx() > cellW {
+			cellW = d.Bounds().Dx()
+		}
+		if d.Bounds().Dy() > cellH {
+			cellH = d.Bounds().Dy()
+		}
+	}
+
+	captionH := 0
+	if captions {
+		captionH = grafanaGridCaptionHeight
+	}
+	rows := (len(images) + columns - 1) / columns
+
+	canvas := image.NewRGBA(image.Rect(0, 0, columns*cellW, rows*(cellH+captionH)))
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+
+	for i, d := range decoded {
+		x := (i % columns) * cellW
+		y := (i / columns) * (cellH + captionH)
+		if captions {
+			caption := images[i].Title
+			if utils.IsEmpty(caption) {
+				caption = fmt.Sprintf("Panel %s", images[i].PanelID)
+			}
+			grafanaGridCaption(canvas, x, y, cellW, caption)
+		}
+		r := image.Rect(x, y+captionH, x+d.Bounds().Dx(), y+captionH+d.Bounds().Dy())
+		draw.Draw(canvas, r, d, d.Bounds().Min, draw.Over)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, canvas); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,340 @@
+package vendors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/devopsext/utils"
+)
+
+// Snapshots and short urls to share interactive dashboards in chats
+
+type GrafanaSnapshotOptions struct {
+	Name    string
+	From    string
+	To      string
+	Expires string // duration, empty never expires
+	Cloned  GrafanaClonedDahboardOptions
+}
+
+type GrafanaShortURLOptions struct {
+	PanelID string
+	From    string
+	To      string
+	Vars    []string // name=value
+}
+
+type GrafanaSnapshotFrameField struct {
+	Name   string            `json:"name"`
+	Type   string            `json:"type"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Config map[string]string `json:"config"`
+	Values []interface{}     `json:"values"`
+}
+
+type GrafanaSnapshotFrame struct {
+	Name   string                       `json:"name,omitempty"`
+	RefID  string                       `json:"refId"`
+	Fields []*GrafanaSnapshotFrameField `json:"fields"`
+}
+
+// grafanaSnapshotVariables takes current values of dashboard variables, multiple values become regex as for prometheus
+func grafanaSnapshotVariables(dashboard map[string]interface{}) *grafanaCloneOverrides {
+
+	vars := make(map[string]string)
+	templating, _ := dashboard["templating"].(map[string]interface{})
+	list, _ := templating["list"].([]interface{})
+	for _, v := range list {
+		vm, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := vm["name"].(string)
+		current, _ := vm["current"].(map[string]interface{})
+		if utils.IsEmpty(name) || current == nil {
+			continue
+		}
+
+		var values []string
+		switch value := current["value"].(type) {
+		case string:
+			values = []string{value}
+		case []interface{}:
+			for _, item := range value {
+				values = append(values, fmt.Sprintf("%v", item))
+			}
+		}
+		if len(values) == 1 && values[0] == "$__all" {
+			all, _ := vm["allValue"].(string)
+			if utils.IsEmpty(all) {
+				all = ".*"
+			}
+			values = []string{all}
+		}
+
+		switch len(values) {
+		case 0:
+			continue
+		case 1:
+			vars[name] = values[0]
+		default:
+			vars[name] = fmt.Sprintf("(%s)", strings.Join(values, "|"))
+		}
+	}
+	return &grafanaCloneOverrides{vars: vars, inline: true}
+}
+
+// snapshotPanelData queries panel targets, snapshot keeps data because datasources are not queried when it's viewed
+func (g *Grafana) snapshotPanelData(grafanaOptions GrafanaOptions, pm map[string]interface{}, vars *grafanaCloneOverrides, from, to string) ([]*GrafanaSnapshotFrame, error) {
+
+	targets, ok := pm["targets"].([]interface{})
+	if !ok || len(targets) == 0 {
+		return nil, nil
+	}
+
+	var queries []interface{}
+	for _, t := range targets {
+		tm, ok := t.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if hide, _ := tm["hide"].(bool); hide {
+			continue
+		}
+		q := make(map[string]interface{})
+		for k, v := range tm {
+			q[k] = v
+		}
+		if _, ok := q["datasource"]; !ok {
+			q["datasource"] = pm["datasource"]
+		}
+		if _, ok := q["refId"]; !ok {
+			q["refId"] = string(rune('A' + len(queries)))
+		}
+		queries = append(queries, vars.inlineVariables(q))
+	}
+	if len(queries) == 0 {
+		return nil, nil
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"queries": queries, "from": from, "to": to})
+	if err != nil {
+		return nil, err
+	}
+
+	// failed queries come with bad request status and error inside results
+	b, _, reqErr := g.request(grafanaOptions, http.MethodPost, "/api/ds/query", nil, body)
+
+	var r GrafanaQueryResponse
+	if err := json.Unmarshal(b, &r); err != nil {
+		if reqErr != nil {
+			return nil, reqErr
+		}
+		return nil, err
+	}
+
+	refIDs := make([]string, 0, len(r.Results))
+	for refID := range r.Results {
+		refIDs = append(refIDs, refID)
+	}
+	sort.Strings(refIDs)
+
+	var errs []string
+	frames := []*GrafanaSnapshotFrame{}
+	for _, refID := range refIDs {
+		if !utils.IsEmpty(r.Results[refID].Error) {
+			errs = append(errs, fmt.Sprintf("%s: %s", refID, r.Results[refID].Error))
+		}
+		for _, frame := range r.Results[refID].Frames {
+			sf := &GrafanaSnapshotFrame{Name: frame.Schema.Name, RefID: refID}
+			for i, f := range frame.Schema.Fields {
+				field := &GrafanaSnapshotFrameField{Name: f.Name, Type: f.Type, Labels: f.Labels, Config: map[string]string{}}
+				if i < len(frame.Data.Values) {
+					field.Values = frame.Data.Values[i]
+				}
+				sf.Fields = append(sf.Fields, field)
+			}
+			frames = append(frames, sf)
+		}
+	}
+	if len(errs) > 0 {
+		return frames, errors.New(strings.Join(errs, "; "))
+	}
+	if reqErr != nil {
+		return frames, reqErr
+	}
+	return frames, nil
+}
+
+// snapshotPanels keeps data of every panel, failed panels are kept with data they have and reported in warnings
+func (g *Grafana) snapshotPanels(grafanaOptions GrafanaOptions, panels []interface{}, vars *grafanaCloneOverrides, from, to string, warnings *[]string) {
+
+	for _, p := range panels {
+		pm, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if g.panelIsType(pm, "row") {
+			if pnls, ok := pm["panels"].([]interface{}); ok {
+				g.snapshotPanels(grafanaOptions, pnls, vars, from, to, warnings)
+			}
+			continue
+		}
+		frames, err := g.snapshotPanelData(grafanaOptions, pm, vars, from, to)
+		if err != nil {
+			*warnings = append(*warnings, fmt.Sprintf("%s: %s", grafanaDiffPanelLabel(pm), err))
+		}
+		if len(frames) > 0 {
+			pm["snapshotData"] = frames
+		}
+	}
+}
+
+// CustomCreateSnapshot snapshots dashboard or board cloned from other one without saving it,
+// panels without data are kept empty and their query errors are returned as warnings with snapshot urls
+func (g *Grafana) CustomCreateSnapshot(grafanaOptions GrafanaOptions, snapshotOptions GrafanaSnapshotOptions) ([]byte, error) {
+
+	var expires int64
+	if !utils.IsEmpty(snapshotOptions.Expires) {
+		d, err := time.ParseDuration(snapshotOptions.Expires)
+		if err != nil {
+			return nil, fmt.Errorf("grafana snapshot expires %s is invalid: %s", snapshotOptions.Expires, err)
+		}
+		expires = int64(d.Seconds())
+	}
+
+	from := snapshotOptions.From
+	if utils.IsEmpty(from) {
+		from = "now-1h"
+	}
+	to := snapshotOptions.To
+	if utils.IsEmpty(to) {
+		to = "now"
+	}
+
+	dashboard := make(map[string]interface{})
+	if !utils.IsEmpty(snapshotOptions.Cloned.UID) {
+		board, err := g.buildDashboard(grafanaOptions, GrafanaCreateDahboardOptions{
+			Title:  snapshotOptions.Name,
+			From:   from,
+			To:     to,
+			Cloned: snapshotOptions.Cloned,
+		})
+		if err != nil {
+			return nil, err
+		}
+		b, err := json.Marshal(board.Dashboard)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, &dashboard); err != nil {
+			return nil, err
+		}
+	} else {
+		if utils.IsEmpty(grafanaOptions.DashboardUID) {
+			return nil, errors.New("grafana snapshot dashboard uid is empty")
+		}
+		b, _, err := g.request(grafanaOptions, http.MethodGet, fmt.Sprintf("/api/dashboards/uid/%s", url.PathEscape(grafanaOptions.DashboardUID)), nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		var r struct {
+			Dashboard map[string]interface{} `json:"dashboard"`
+		}
+		if err := json.Unmarshal(b, &r); err != nil {
+			return nil, err
+		}
+		if r.Dashboard != nil {
+			dashboard = r.Dashboard
+		}
+	}
+
+	name := snapshotOptions.Name
+	if utils.IsEmpty(name) {
+		name, _ = dashboard["title"].(string)
+	}
+	delete(dashboard, "id")
+	delete(dashboard, "uid")
+	dashboard["time"] = map[string]interface{}{"from": from, "to": to}
+
+	var warnings []string
+	panels, _ := dashboard["panels"].([]interface{})
+	g.snapshotPanels(grafanaOptions, panels, grafanaSnapshotVariables(dashboard), g.toRFC3339NanoStr(from), g.toRFC3339NanoStr(to), &warnings)
+
+	body, err := json.Marshal(map[string]interface{}{
+		"dashboard": dashboard,
+		"name":      name,
+		"expires":   expires,
+	})
+	if err != nil {
+		return nil, err
+	}
+	b, _, err := g.request(grafanaOptions, http.MethodPost, "/api/snapshots", nil, body)
+	if err != nil || len(warnings) == 0 {
+		return b, err
+	}
+
+	r := make(map[string]interface{})
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, err
+	}
+	r["warnings"] = warnings
+	return json.Marshal(r)
+}
+
+func (g *Grafana) CreateSnapshot(options GrafanaSnapshotOptions) ([]byte, error) {
+	return g.CustomCreateSnapshot(g.options, options)
+}
+
+// CustomCreateShortURL shortens dashboard link, panel is opened in view mode
+func (g *Grafana) CustomCreateShortURL(grafanaOptions GrafanaOptions, shortURLOptions GrafanaShortURLOptions) ([]byte, error) {
+
+	if utils.IsEmpty(grafanaOptions.DashboardUID) {
+		return nil, errors.New("grafana short url dashboard uid is empty")
+	}
+
+	names, vars, err := grafanaKeyValues(shortURLOptions.Vars, "variable", "<name>=<value>")
+	if err != nil {
+		return nil, err
+	}
+
+	params := make(url.Values)
+	if !utils.IsEmpty(grafanaOptions.OrgID) {
+		params.Add("orgId", grafanaOptions.OrgID)
+	}
+	if !utils.IsEmpty(shortURLOptions.From) {
+		params.Add("from", g.toRFC3339NanoStr(shortURLOptions.From))
+	}
+	if !utils.IsEmpty(shortURLOptions.To) {
+		params.Add("to", g.toRFC3339NanoStr(shortURLOptions.To))
+	}
+	if !utils.IsEmpty(shortURLOptions.PanelID) {
+		params.Add("viewPanel", shortURLOptions.PanelID)
+	}
+	for _, name := range names {
+		params.Add(fmt.Sprintf("var-%s", name), vars[name])
+	}
+
+	p := path.Join("d", grafanaOptions.DashboardUID, grafanaOptions.DashboardSlug)
+	if len(params) > 0 {
+		p = fmt.Sprintf("%s?%s", p, params.Encode())
+	}
+
+	body, err := json.Marshal(map[string]string{"path": p})
+	if err != nil {
+		return nil, err
+	}
+	b, _, err := g.request(grafanaOptions, http.MethodPost, "/api/short-urls", nil, body)
+	return b, err
+}
+
+func (g *Grafana) CreateShortURL(options GrafanaShortURLOptions) ([]byte, error) {
+	return g.CustomCreateShortURL(g.options, options)
+}
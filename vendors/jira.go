@@ -4,12 +4,16 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"mime/multipart"
 	"net/http"
 	"net/url"
 	"path"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/devopsext/tools/common"
 	"github.com/devopsext/utils"
@@ -26,6 +30,9 @@ type JiraOptions struct {
 	User        string
 	Password    string
 	AccessToken string
+	Email       string
+	APIToken    string
+	APIVersion  int // 2 by default, 3 for Jira Cloud with ADF rich text and account IDs
 }
 
 type JiraIssueOptions struct {
@@ -53,27 +60,134 @@ type JiraAddIssueAttachmentOptions struct {
 
 type JiraSearchIssueOptions struct {
 	SearchPattern string
-	MaxResults    int
+	MaxResults    int // page size
+	Fields        []string
+	Expand        []string
+	Limit         int  // max issues to return in total, 0 means all
+	NextPageToken bool // use Jira Cloud /search/jql paging by nextPageToken
+}
+
+type JiraSearchIssueResult struct {
+	Total  int               `json:"total"`
+	Issues []json.RawMessage `json:"issues"`
+}
+
+type JiraTransitionIssueOptions struct {
+	To         string // transition name, target status name or transition ID
+	Resolution string
+	Comment    string
+	Fields     string
+}
+
+type JiraTransitionIssueResult struct {
+	Key        string          `json:"key"`
+	Transition *JiraTransition `json:"transition"`
+	Status     string          `json:"status"`
+}
+
+type JiraUpsertIssueOptions struct {
+	Fingerprint      string
+	FingerprintField string // label if empty, custom field ID otherwise (customfield_10010)
+	Comment          string
+	BumpPriority     bool
+	ReopenTransition string
+	ReopenWindow     string // relative JQL duration, e.g. 7d
+}
+
+type JiraUpsertIssueResult struct {
+	Action         string `json:"action"`
+	ID             string `json:"id,omitempty"`
+	Key            string `json:"key,omitempty"`
+	PriorityBumped bool   `json:"priorityBumped,omitempty"`
+}
+
+type JiraGetIssueOptions struct {
+	Fields []string
+	Expand []string
+}
+
+type JiraLinkIssueOptions struct {
+	Type    string // link type name, outward or inward description
+	To      string
+	Comment string
+}
+
+type JiraAddIssueWatcherOptions struct {
+	User string // user name or account ID for API v3
+}
+
+type JiraAddIssueWorklogOptions struct {
+	TimeSpent string
+	Started   string
+	Comment   string
+}
+
+type JiraAddIssueRemoteLinkOptions struct {
+	URL          string
+	Title        string
+	Summary      string
+	Icon         string
+	GlobalID     string
+	Relationship string
+}
+
+type JiraBulkCreateIssueOptions struct {
+	Input     string
+	Format    string // ndjson, csv
+	Template  string
+	State     string
+	ChunkSize int
+	IDField   string // record field to identify it in state, record hash if empty
+}
+
+type JiraBulkIssue struct {
+	Row    int
+	Record string
+	Fields map[string]interface{}
+}
+
+type JiraBulkCreateIssueResult struct {
+	Row    int    `json:"row"`
+	Record string `json:"record,omitempty"`
+	ID     string `json:"id,omitempty"`
+	Key    string `json:"key,omitempty"`
+	Error  string `json:"error,omitempty"`
 }
 
 type JiraSearchAssetsOptions struct {
 	SearchPattern string
 	ResultPerPage int
+	Limit         int  // max objects to return, all if empty
+	Flatten       bool // objects as map of attribute name to value
 }
 
+// attributes are json map of attribute name or id to value (or list of values), content or path
 type JiraCreateAssetOptions struct {
-	Description    string
-	Name           string
-	Repository     string
-	DescriptionId  int
-	NameId         int
-	RepositoryId   int
 	ObjectSchemeId string
 	ObjectTypeId   int
-	TitleId        int
-	Title          string
-	TierId         int
-	Tier           string
+	Attributes     string
+}
+
+type JiraUpdateAssetOptions struct {
+	ObjectId     string
+	ObjectTypeId int // taken from object if empty
+	Attributes   string
+}
+
+type JiraGetAssetOptions struct {
+	ObjectId string
+}
+
+type JiraDeleteAssetOptions struct {
+	ObjectId string
+}
+
+// objects are json list of attribute maps, content or path
+type JiraSyncAssetsOptions struct {
+	ObjectSchemeId string
+	ObjectTypeId   int
+	KeyAttribute   string
+	Objects        string
 }
 
 type JiraIssueCreate struct {
@@ -87,15 +201,20 @@ type JiraIssueFields struct {
 	Project     *JiraIssueProject  `json:"project,omitempty"`
 	IssueType   *JiraIssueType     `json:"issuetype,omitempty"`
 	Summary     string             `json:"summary,omitempty"`
-	Description string             `json:"description,omitempty"`
+	Description interface{}        `json:"description,omitempty"`
 	Labels      []string           `json:"labels,omitempty"`
 	Priority    *JiraIssuePriority `json:"priority,omitempty"`
 	Assignee    *JiraIssueAssignee `json:"assignee,omitempty"`
 	Reporter    *JiraIssueReporter `json:"reporter,omitempty"`
 }
 
+type JiraIssueRef struct {
+	ID  string `json:"id,omitempty"`
+	Key string `json:"key,omitempty"`
+}
+
 type JiraIssueAddComment struct {
-	Body string `json:"body"`
+	Body interface{} `json:"body"`
 }
 
 type JiraIssueType struct {
@@ -109,26 +228,83 @@ type JiraIssuePriority struct {
 	Name string `json:"name"`
 }
 
-type JiraIssueAssignee struct {
+type JiraIssueResolution struct {
 	Name string `json:"name"`
 }
 
+type JiraIssueAssignee struct {
+	Name      string `json:"name,omitempty"`
+	AccountID string `json:"accountId,omitempty"`
+}
+
 type JiraIssueReporter struct {
-	Name string `json:"name"`
+	Name      string `json:"name,omitempty"`
+	AccountID string `json:"accountId,omitempty"`
 }
 
 type JiraTransition struct {
-	ID string `json:"id"`
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
 }
 
 type JiraIssueTransition struct {
-	Transition *JiraTransition `json:"transition"`
+	Transition *JiraTransition        `json:"transition"`
+	Fields     map[string]interface{} `json:"fields,omitempty"`
+	Update     map[string]interface{} `json:"update,omitempty"`
+}
+
+type JiraIssueTransitions struct {
+	Transitions []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+		To   struct {
+			Name string `json:"name"`
+		} `json:"to"`
+	} `json:"transitions"`
 }
 
 type OutputCode struct {
 	Code int `json:"code"`
 }
 
+type JiraIssueLinkType struct {
+	ID      string `json:"id,omitempty"`
+	Name    string `json:"name"`
+	Inward  string `json:"inward,omitempty"`
+	Outward string `json:"outward,omitempty"`
+}
+
+type JiraIssueLink struct {
+	Type         *JiraIssueLinkType   `json:"type"`
+	InwardIssue  *JiraIssueRef        `json:"inwardIssue"`
+	OutwardIssue *JiraIssueRef        `json:"outwardIssue"`
+	Comment      *JiraIssueAddComment `json:"comment,omitempty"`
+}
+
+type JiraIssueWorklog struct {
+	TimeSpent string      `json:"timeSpent"`
+	Started   string      `json:"started,omitempty"`
+	Comment   interface{} `json:"comment,omitempty"`
+}
+
+type JiraIssueRemoteLinkIcon struct {
+	URL16x16 string `json:"url16x16,omitempty"`
+	Title    string `json:"title,omitempty"`
+}
+
+type JiraIssueRemoteLinkObject struct {
+	URL     string                   `json:"url"`
+	Title   string                   `json:"title"`
+	Summary string                   `json:"summary,omitempty"`
+	Icon    *JiraIssueRemoteLinkIcon `json:"icon,omitempty"`
+}
+
+type JiraIssueRemoteLink struct {
+	GlobalID     string                     `json:"globalId,omitempty"`
+	Relationship string                     `json:"relationship,omitempty"`
+	Object       *JiraIssueRemoteLinkObject `json:"object"`
+}
+
 type JiraAssetAttributeValue struct {
 	Value string `json:"value"`
 }
@@ -143,56 +319,181 @@ type JiraAsset struct {
 	Attributes   []JiraAssetAttribute `json:"attributes"`
 }
 
+type JiraAssetTypeAttribute struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type JiraAssetObjectType struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type JiraAssetObjectAttributeValue struct {
+	Value            interface{}      `json:"value"`
+	DisplayValue     interface{}      `json:"displayValue,omitempty"`
+	ReferencedObject *JiraAssetObject `json:"referencedObject,omitempty"`
+}
+
+type JiraAssetObjectAttribute struct {
+	ObjectTypeAttributeId int                              `json:"objectTypeAttributeId"`
+	ObjectAttributeValues []*JiraAssetObjectAttributeValue `json:"objectAttributeValues"`
+}
+
+type JiraAssetObject struct {
+	ID         int                         `json:"id"`
+	ObjectKey  string                      `json:"objectKey"`
+	Label      string                      `json:"label"`
+	ObjectType *JiraAssetObjectType        `json:"objectType,omitempty"`
+	Attributes []*JiraAssetObjectAttribute `json:"attributes,omitempty"`
+}
+
+type JiraSyncAssetResult struct {
+	Key       string `json:"key"`
+	Action    string `json:"action"`
+	ID        int    `json:"id,omitempty"`
+	ObjectKey string `json:"objectKey,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+func jiraAssetsPath(format string, args ...interface{}) string {
+	return path.Join("rest/assets/1.0", fmt.Sprintf(format, args...))
+}
+
+func jiraAssetValue(v interface{}) string {
+
+	switch t := v.(type) {
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprintf("%v", t)
+	}
+}
+
+func jiraAssetValues(v interface{}) []string {
+
+	var values []string
+	switch t := v.(type) {
+	case nil:
+	case []interface{}:
+		for _, i := range t {
+			values = append(values, jiraAssetValue(i))
+		}
+	default:
+		values = append(values, jiraAssetValue(t))
+	}
+	return values
+}
+
+func jiraAqlQuote(s string) string {
+	return fmt.Sprintf("\"%s\"", strings.ReplaceAll(s, "\"", "\\\""))
+}
+
+func (j *Jira) getAssetTypeAttributes(jiraOptions JiraOptions, objectTypeId int) ([]*JiraAssetTypeAttribute, error) {
+
+	u, err := url.Parse(jiraOptions.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, jiraAssetsPath("objecttype/%d/attributes", objectTypeId))
+
+	bytes, err := utils.HttpGetRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions))
+	if err != nil {
+		return nil, err
+	}
+
+	var attributes []*JiraAssetTypeAttribute
+	if err := json.Unmarshal(bytes, &attributes); err != nil {
+		return nil, err
+	}
+	return attributes, nil
+}
+
+func jiraAssetTypeAttributeID(schema []*JiraAssetTypeAttribute, objectTypeId int, name string) (int, error) {
+
+	if id, err := strconv.Atoi(name); err == nil {
+		return id, nil
+	}
+	for _, a := range schema {
+		if strings.EqualFold(a.Name, name) {
+			return a.ID, nil
+		}
+	}
+	return 0, fmt.Errorf("jira asset attribute %s is not found in object type %d", name, objectTypeId)
+}
+
+// jiraAssetBuildAttributes resolves attribute names into ids of object type schema
+func jiraAssetBuildAttributes(schema []*JiraAssetTypeAttribute, objectTypeId int, values map[string]interface{}) ([]JiraAssetAttribute, error) {
+
+	names := make([]string, 0, len(values))
+	for k := range values {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	attributes := []JiraAssetAttribute{}
+	for _, name := range names {
+
+		id, err := jiraAssetTypeAttributeID(schema, objectTypeId, name)
+		if err != nil {
+			return nil, err
+		}
+		attribute := JiraAssetAttribute{
+			ObjectTypeAttributeId: id,
+			ObjectAttributeValues: []JiraAssetAttributeValue{},
+		}
+		for _, v := range jiraAssetValues(values[name]) {
+			attribute.ObjectAttributeValues = append(attribute.ObjectAttributeValues, JiraAssetAttributeValue{Value: v})
+		}
+		attributes = append(attributes, attribute)
+	}
+	return attributes, nil
+}
+
+func (j *Jira) assetAttributes(jiraOptions JiraOptions, objectTypeId int, attributes string) ([]JiraAssetAttribute, error) {
+
+	if objectTypeId == 0 {
+		return nil, errors.New("jira asset object type id is empty")
+	}
+
+	values := make(map[string]interface{})
+	if !utils.IsEmpty(attributes) {
+		var err error
+		values, err = common.ReadAndMarshal(attributes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	schema, err := j.getAssetTypeAttributes(jiraOptions, objectTypeId)
+	if err != nil {
+		return nil, err
+	}
+	return jiraAssetBuildAttributes(schema, objectTypeId, values)
+}
+
 func (j *Jira) CreateAsset(objectCreateOptions JiraCreateAssetOptions) ([]byte, error) {
 	return j.CustomCreateAsset(j.options, objectCreateOptions)
 }
 
 func (j *Jira) CustomCreateAsset(jiraOptions JiraOptions, createOptions JiraCreateAssetOptions) ([]byte, error) {
 
+	attributes, err := j.assetAttributes(jiraOptions, createOptions.ObjectTypeId, createOptions.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	return j.createAsset(jiraOptions, createOptions.ObjectSchemeId, createOptions.ObjectTypeId, attributes)
+}
+
+func (j *Jira) createAsset(jiraOptions JiraOptions, objectSchemeId string, objectTypeId int, attributes []JiraAssetAttribute) ([]byte, error) {
+
 	object := &JiraAsset{
-		ObjectTypeId: createOptions.ObjectTypeId,
-		Attributes: []JiraAssetAttribute{
-			{
-				ObjectTypeAttributeId: createOptions.NameId,
-				ObjectAttributeValues: []JiraAssetAttributeValue{
-					{
-						Value: createOptions.Name,
-					},
-				},
-			},
-			{
-				ObjectTypeAttributeId: createOptions.DescriptionId,
-				ObjectAttributeValues: []JiraAssetAttributeValue{
-					{
-						Value: createOptions.Description,
-					},
-				},
-			},
-			{
-				ObjectTypeAttributeId: createOptions.TierId,
-				ObjectAttributeValues: []JiraAssetAttributeValue{
-					{
-						Value: createOptions.Tier,
-					},
-				},
-			},
-			{
-				ObjectTypeAttributeId: createOptions.RepositoryId,
-				ObjectAttributeValues: []JiraAssetAttributeValue{
-					{
-						Value: createOptions.Repository,
-					},
-				},
-			},
-			{
-				ObjectTypeAttributeId: createOptions.TitleId,
-				ObjectAttributeValues: []JiraAssetAttributeValue{
-					{
-						Value: createOptions.Title,
-					},
-				},
-			},
-		},
+		ObjectTypeId: objectTypeId,
+		Attributes:   attributes,
 	}
 
 	req, err := json.Marshal(object)
@@ -206,8 +507,10 @@ func (j *Jira) CustomCreateAsset(jiraOptions JiraOptions, createOptions JiraCrea
 	}
 
 	params := make(url.Values)
-	params.Add("objectSchemaId", createOptions.ObjectSchemeId)
-	u.Path = path.Join(u.Path, "rest/assets/1.0/object/create")
+	if !utils.IsEmpty(objectSchemeId) {
+		params.Add("objectSchemaId", objectSchemeId)
+	}
+	u.Path = path.Join(u.Path, jiraAssetsPath("object/create"))
 	u.RawQuery = params.Encode()
 	return utils.HttpPostRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions), req)
 }
@@ -231,20 +534,14 @@ func jsonJiraMarshal(issue interface{}, cf map[string]interface{}) ([]byte, erro
 	return json.Marshal(m)
 }
 
-// we need custom json unmarshal for Jira Assets to support pagination
-func jsonJiraAssetsUnmarshal(a []byte) (map[string]interface{}, error) {
-	var assets interface{}
-	err := json.Unmarshal(a, &assets)
-	if err != nil {
-		return nil, err
-	}
-	m := assets.(map[string]interface{})
-	return m, nil
-}
-
 func (j *Jira) getAuth(opts JiraOptions) string {
 
 	auth := ""
+	if !utils.IsEmpty(opts.Email) && !utils.IsEmpty(opts.APIToken) {
+		userPass := fmt.Sprintf("%s:%s", opts.Email, opts.APIToken)
+		auth = fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte(userPass)))
+		return auth
+	}
 	if !utils.IsEmpty(opts.User) {
 		userPass := fmt.Sprintf("%s:%s", opts.User, opts.Password)
 		auth = fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte(userPass)))
@@ -257,7 +554,52 @@ func (j *Jira) getAuth(opts JiraOptions) string {
 	return auth
 }
 
+func (j *Jira) isCloud(opts JiraOptions) bool {
+	return opts.APIVersion >= 3
+}
+
+func (j *Jira) apiPath(opts JiraOptions, format string, args ...interface{}) string {
+
+	version := opts.APIVersion
+	if version == 0 {
+		version = 2
+	}
+	return fmt.Sprintf("/rest/api/%d/%s", version, fmt.Sprintf(format, args...))
+}
+
+// rich text is wiki markup for v2 and ADF for v3
+func (j *Jira) richText(opts JiraOptions, text string) interface{} {
+
+	if utils.IsEmpty(text) {
+		return nil
+	}
+	if j.isCloud(opts) {
+		return JiraMarkdownToADF(text)
+	}
+	return text
+}
+
+func (j *Jira) getAssignee(opts JiraOptions, user string) *JiraIssueAssignee {
+
+	if j.isCloud(opts) {
+		return &JiraIssueAssignee{AccountID: user}
+	}
+	return &JiraIssueAssignee{Name: user}
+}
+
+func (j *Jira) getReporter(opts JiraOptions, user string) *JiraIssueReporter {
+
+	if j.isCloud(opts) {
+		return &JiraIssueReporter{AccountID: user}
+	}
+	return &JiraIssueReporter{Name: user}
+}
+
 func (j *Jira) CustomCreateIssue(jiraOptions JiraOptions, createOptions JiraIssueOptions) ([]byte, error) {
+	return j.createIssue(jiraOptions, createOptions, nil)
+}
+
+func (j *Jira) createIssue(jiraOptions JiraOptions, createOptions JiraIssueOptions, fields map[string]interface{}) ([]byte, error) {
 
 	issue := &JiraIssueCreate{
 		Fields: &JiraIssueFields{
@@ -268,7 +610,8 @@ func (j *Jira) CustomCreateIssue(jiraOptions JiraOptions, createOptions JiraIssu
 				Name: createOptions.Type,
 			},
 			Summary:     createOptions.Summary,
-			Description: createOptions.Description,
+			Description: j.richText(jiraOptions, createOptions.Description),
+			Labels:      common.RemoveEmptyStrings(createOptions.Labels),
 		},
 	}
 
@@ -279,15 +622,11 @@ func (j *Jira) CustomCreateIssue(jiraOptions JiraOptions, createOptions JiraIssu
 	}
 
 	if !utils.IsEmpty(createOptions.Assignee) {
-		issue.Fields.Assignee = &JiraIssueAssignee{
-			Name: createOptions.Assignee,
-		}
+		issue.Fields.Assignee = j.getAssignee(jiraOptions, createOptions.Assignee)
 	}
 
 	if !utils.IsEmpty(createOptions.Reporter) {
-		issue.Fields.Reporter = &JiraIssueReporter{
-			Name: createOptions.Reporter,
-		}
+		issue.Fields.Reporter = j.getReporter(jiraOptions, createOptions.Reporter)
 	}
 
 	cf := make(map[string]interface{})
@@ -299,6 +638,9 @@ func (j *Jira) CustomCreateIssue(jiraOptions JiraOptions, createOptions JiraIssu
 			return nil, err
 		}
 	}
+	for k, v := range fields {
+		cf[k] = v
+	}
 
 	req, err := jsonJiraMarshal(&issue, cf)
 	if err != nil {
@@ -309,7 +651,7 @@ func (j *Jira) CustomCreateIssue(jiraOptions JiraOptions, createOptions JiraIssu
 	if err != nil {
 		return nil, err
 	}
-	u.Path = path.Join(u.Path, "/rest/api/2/issue")
+	u.Path = path.Join(u.Path, j.apiPath(jiraOptions, "issue"))
 	return utils.HttpPostRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions), req)
 }
 
@@ -320,7 +662,7 @@ func (j *Jira) CreateIssue(issueCreateOptions JiraIssueOptions) ([]byte, error)
 func (j *Jira) CustomAddIssueComment(jiraOptions JiraOptions, issueOptions JiraIssueOptions, addCommentOptions JiraAddIssueCommentOptions) ([]byte, error) {
 
 	comment := &JiraIssueAddComment{
-		Body: addCommentOptions.Body,
+		Body: j.richText(jiraOptions, addCommentOptions.Body),
 	}
 
 	req, err := json.Marshal(&comment)
@@ -332,7 +674,7 @@ func (j *Jira) CustomAddIssueComment(jiraOptions JiraOptions, issueOptions JiraI
 	if err != nil {
 		return nil, err
 	}
-	u.Path = path.Join(u.Path, fmt.Sprintf("/rest/api/2/issue/%s/comment", issueOptions.IdOrKey))
+	u.Path = path.Join(u.Path, j.apiPath(jiraOptions, "issue/%s/comment", issueOptions.IdOrKey))
 	return utils.HttpPostRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions), req)
 }
 
@@ -346,7 +688,7 @@ func (j *Jira) CustomAddIssueAttachment(jiraOptions JiraOptions, issueOptions Ji
 	if err != nil {
 		return nil, err
 	}
-	u.Path = path.Join(u.Path, fmt.Sprintf("/rest/api/2/issue/%s/attachments", issueOptions.IdOrKey))
+	u.Path = path.Join(u.Path, j.apiPath(jiraOptions, "issue/%s/attachments", issueOptions.IdOrKey))
 
 	var body bytes.Buffer
 	w := multipart.NewWriter(&body)
@@ -383,7 +725,7 @@ func (j *Jira) CustomUpdateIssue(jiraOptions JiraOptions, issueOptions JiraIssue
 	issue := &JiraIssueUpdate{
 		Fields: &JiraIssueFields{
 			Summary:     issueOptions.Summary,
-			Description: issueOptions.Description,
+			Description: j.richText(jiraOptions, issueOptions.Description),
 		},
 	}
 
@@ -415,7 +757,7 @@ func (j *Jira) CustomUpdateIssue(jiraOptions JiraOptions, issueOptions JiraIssue
 	if err != nil {
 		return nil, err
 	}
-	u.Path = path.Join(u.Path, fmt.Sprintf("/rest/api/2/issue/%s", issueOptions.IdOrKey))
+	u.Path = path.Join(u.Path, j.apiPath(jiraOptions, "issue/%s", issueOptions.IdOrKey))
 	return utils.HttpPutRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions), req)
 }
 
@@ -438,7 +780,7 @@ func (j *Jira) CustomChangeIssueTransitions(jiraOptions JiraOptions, issueOption
 	if err != nil {
 		return nil, err
 	}
-	u.Path = path.Join(u.Path, fmt.Sprintf("/rest/api/2/issue/%s/transitions", issueOptions.IdOrKey))
+	u.Path = path.Join(u.Path, j.apiPath(jiraOptions, "issue/%s/transitions", issueOptions.IdOrKey))
 
 	_, c, err := utils.HttpPostRawOutCode(j.client, u.String(), "application/json", j.getAuth(jiraOptions), req)
 	if err != nil {
@@ -459,80 +801,1167 @@ func (j *Jira) ChangeIssueTransitions(options JiraIssueOptions) ([]byte, error)
 	return j.CustomChangeIssueTransitions(j.options, options)
 }
 
-func (j *Jira) CustomSearchIssue(jiraOptions JiraOptions, search JiraSearchIssueOptions) ([]byte, error) {
+func (j *Jira) CustomTransitionIssue(jiraOptions JiraOptions, issueOptions JiraIssueOptions, transitionOptions JiraTransitionIssueOptions) ([]byte, error) {
 
-	params := make(url.Values)
-	params.Add("jql", search.SearchPattern)
-	params.Add("maxResults", strconv.Itoa(search.MaxResults))
-	params.Add("validateQuery", "strict")
+	if utils.IsEmpty(transitionOptions.To) {
+		return nil, errors.New("jira transition target is empty")
+	}
 
 	u, err := url.Parse(jiraOptions.URL)
 	if err != nil {
 		return nil, err
 	}
+	u.Path = path.Join(u.Path, j.apiPath(jiraOptions, "issue/%s/transitions", issueOptions.IdOrKey))
 
-	u.Path = path.Join(u.Path, "/rest/api/2/search")
-	u.RawQuery = params.Encode()
+	bytes, err := utils.HttpGetRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions))
+	if err != nil {
+		return nil, err
+	}
 
-	return utils.HttpGetRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions))
-}
+	var transitions JiraIssueTransitions
+	if err := json.Unmarshal(bytes, &transitions); err != nil {
+		return nil, err
+	}
 
-func (j *Jira) SearchIssue(options JiraSearchIssueOptions) ([]byte, error) {
-	return j.CustomSearchIssue(j.options, options)
-}
+	var transition *JiraTransition
+	var available []string
+	for _, t := range transitions.Transitions {
+		if t.ID == transitionOptions.To || strings.EqualFold(t.Name, transitionOptions.To) || strings.EqualFold(t.To.Name, transitionOptions.To) {
+			transition = &JiraTransition{ID: t.ID, Name: t.Name}
+			break
+		}
+		available = append(available, fmt.Sprintf("%s (%s) => %s", t.Name, t.ID, t.To.Name))
+	}
+	if transition == nil {
+		return nil, fmt.Errorf("jira issue %s cannot be transitioned to %s, available transitions: %s", issueOptions.IdOrKey, transitionOptions.To, strings.Join(available, ", "))
+	}
 
-func (j *Jira) CustomSearchAssets(jiraOptions JiraOptions, search JiraSearchAssetsOptions) ([]byte, error) {
+	req := &JiraIssueTransition{
+		Transition: &JiraTransition{ID: transition.ID},
+		Fields:     make(map[string]interface{}),
+	}
 
-	params := make(url.Values)
-	params.Add("qlQuery", search.SearchPattern)
-	params.Add("resultPerPage", strconv.Itoa(search.ResultPerPage))
+	if !utils.IsEmpty(transitionOptions.Fields) {
+		fields, err := common.ReadAndMarshal(transitionOptions.Fields)
+		if err != nil {
+			return nil, err
+		}
+		if fields != nil {
+			req.Fields = fields
+		}
+	}
+	if !utils.IsEmpty(transitionOptions.Resolution) {
+		req.Fields["resolution"] = &JiraIssueResolution{Name: transitionOptions.Resolution}
+	}
+	if !utils.IsEmpty(transitionOptions.Comment) {
+		req.Update = map[string]interface{}{
+			"comment": []interface{}{
+				map[string]interface{}{"add": &JiraIssueAddComment{Body: j.richText(jiraOptions, transitionOptions.Comment)}},
+			},
+		}
+	}
 
-	u, err := url.Parse(jiraOptions.URL)
+	data, err := json.Marshal(req)
 	if err != nil {
 		return nil, err
 	}
 
-	u.Path = path.Join(u.Path, "/rest/insight/1.0/aql/objects")
-	u.RawQuery = params.Encode()
-	a, err := utils.HttpGetRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions))
+	_, err = utils.HttpPostRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions), data)
 	if err != nil {
 		return nil, err
 	}
 
-	// We need to check if there is a pagination in the answer, if so we need to get all results
-	m, err := jsonJiraAssetsUnmarshal(a)
+	u, err = url.Parse(jiraOptions.URL)
 	if err != nil {
 		return nil, err
 	}
-	assetsObj := m["objectEntries"].([]interface{})
-	objAttr := m["objectTypeAttributes"].([]interface{})
-	pageSize := m["pageSize"].(float64)
-	if pageSize > 1 {
-		for i := 2; i <= int(pageSize); i++ {
-			params.Set("page", strconv.Itoa(i))
-			u.RawQuery = params.Encode()
-			a, err := utils.HttpGetRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions))
-			if err != nil {
-				return nil, err
-			}
-			m, err := jsonJiraAssetsUnmarshal(a)
-			if err != nil {
-				return nil, err
-			}
-			assetsObjPage := m["objectEntries"].([]interface{})
-			assetsObj = append(assetsObj, assetsObjPage...)
-		}
+	u.Path = path.Join(u.Path, j.apiPath(jiraOptions, "issue/%s", issueOptions.IdOrKey))
+	u.RawQuery = "fields=status"
 
+	bytes, err = utils.HttpGetRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions))
+	if err != nil {
+		return nil, err
 	}
-	result := map[string]interface{}{
-		"objects":    assetsObj,
-		"attributes": objAttr,
+
+	var issue struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Status struct {
+				Name string `json:"name"`
+			} `json:"status"`
+		} `json:"fields"`
 	}
-	return json.Marshal(result)
+	if err := json.Unmarshal(bytes, &issue); err != nil {
+		return nil, err
+	}
+
+	return common.JsonMarshal(&JiraTransitionIssueResult{
+		Key:        issue.Key,
+		Transition: transition,
+		Status:     issue.Fields.Status.Name,
+	})
 }
 
-func (j *Jira) SearchAssets(options JiraSearchAssetsOptions) ([]byte, error) {
-	return j.CustomSearchAssets(j.options, options)
+func (j *Jira) TransitionIssue(issueOptions JiraIssueOptions, transitionOptions JiraTransitionIssueOptions) ([]byte, error) {
+	return j.CustomTransitionIssue(j.options, issueOptions, transitionOptions)
+}
+
+func (j *Jira) searchIssuePage(jiraOptions JiraOptions, search JiraSearchIssueOptions, startAt, maxResults int, token string) ([]byte, error) {
+
+	params := make(url.Values)
+	params.Add("jql", search.SearchPattern)
+	params.Add("maxResults", strconv.Itoa(maxResults))
+
+	fields := common.RemoveEmptyStrings(search.Fields)
+	if len(fields) == 0 && search.NextPageToken {
+		// Jira Cloud returns only issue IDs if no fields requested
+		fields = []string{"*navigable"}
+	}
+	if len(fields) > 0 {
+		params.Add("fields", strings.Join(fields, ","))
+	}
+	expand := common.RemoveEmptyStrings(search.Expand)
+	if len(expand) > 0 {
+		params.Add("expand", strings.Join(expand, ","))
+	}
+
+	u, err := url.Parse(jiraOptions.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	if search.NextPageToken {
+		if !utils.IsEmpty(token) {
+			params.Add("nextPageToken", token)
+		}
+		u.Path = path.Join(u.Path, j.apiPath(jiraOptions, "search/jql"))
+	} else {
+		params.Add("startAt", strconv.Itoa(startAt))
+		params.Add("validateQuery", "strict")
+		u.Path = path.Join(u.Path, j.apiPath(jiraOptions, "search"))
+	}
+	u.RawQuery = params.Encode()
+
+	return utils.HttpGetRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions))
+}
+
+// CustomSearchIssueFunc pages through search results and calls fn for every issue, returns total reported by Jira
+func (j *Jira) CustomSearchIssueFunc(jiraOptions JiraOptions, search JiraSearchIssueOptions, fn func(issue json.RawMessage) error) (int, error) {
+
+	pageSize := search.MaxResults
+	if pageSize <= 0 {
+		pageSize = 50
+	}
+
+	count := 0
+	total := 0
+	token := ""
+	for {
+		maxResults := pageSize
+		if search.Limit > 0 && search.Limit-count < maxResults {
+			maxResults = search.Limit - count
+		}
+
+		bytes, err := j.searchIssuePage(jiraOptions, search, count, maxResults, token)
+		if err != nil {
+			return total, err
+		}
+
+		var page struct {
+			Total         int               `json:"total"`
+			Issues        []json.RawMessage `json:"issues"`
+			NextPageToken string            `json:"nextPageToken"`
+			IsLast        bool              `json:"isLast"`
+		}
+		if err := json.Unmarshal(bytes, &page); err != nil {
+			return total, err
+		}
+
+		for _, issue := range page.Issues {
+			if err := fn(issue); err != nil {
+				return total, err
+			}
+		}
+		count += len(page.Issues)
+
+		if search.NextPageToken {
+			total = count
+			token = page.NextPageToken
+			if page.IsLast || utils.IsEmpty(token) {
+				break
+			}
+		} else {
+			total = page.Total
+			if count >= page.Total {
+				break
+			}
+		}
+		if len(page.Issues) == 0 || (search.Limit > 0 && count >= search.Limit) {
+			break
+		}
+	}
+	return total, nil
+}
+
+func (j *Jira) SearchIssueFunc(options JiraSearchIssueOptions, fn func(issue json.RawMessage) error) (int, error) {
+	return j.CustomSearchIssueFunc(j.options, options, fn)
+}
+
+func (j *Jira) CustomSearchIssue(jiraOptions JiraOptions, search JiraSearchIssueOptions) ([]byte, error) {
+
+	result := &JiraSearchIssueResult{
+		Issues: []json.RawMessage{},
+	}
+
+	total, err := j.CustomSearchIssueFunc(jiraOptions, search, func(issue json.RawMessage) error {
+		result.Issues = append(result.Issues, issue)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Total = total
+	return json.Marshal(result)
+}
+
+func (j *Jira) SearchIssue(options JiraSearchIssueOptions) ([]byte, error) {
+	return j.CustomSearchIssue(j.options, options)
+}
+
+func jiraJqlQuote(s string) string {
+	return fmt.Sprintf("\"%s\"", strings.ReplaceAll(strings.ReplaceAll(s, "\\", "\\\\"), "\"", "\\\""))
+}
+
+func (j *Jira) fingerprintJql(issueOptions JiraIssueOptions, upsertOptions JiraUpsertIssueOptions) string {
+
+	var jql []string
+	if !utils.IsEmpty(issueOptions.ProjectKey) {
+		jql = append(jql, fmt.Sprintf("project = %s", jiraJqlQuote(issueOptions.ProjectKey)))
+	}
+
+	field := upsertOptions.FingerprintField
+	switch {
+	case utils.IsEmpty(field):
+		jql = append(jql, fmt.Sprintf("labels = %s", jiraJqlQuote(upsertOptions.Fingerprint)))
+	case strings.HasPrefix(field, "customfield_"):
+		jql = append(jql, fmt.Sprintf("cf[%s] ~ %s", strings.TrimPrefix(field, "customfield_"), jiraJqlQuote(jiraJqlQuote(upsertOptions.Fingerprint))))
+	default:
+		jql = append(jql, fmt.Sprintf("%s ~ %s", jiraJqlQuote(field), jiraJqlQuote(jiraJqlQuote(upsertOptions.Fingerprint))))
+	}
+	return strings.Join(jql, " AND ")
+}
+
+func (j *Jira) findIssue(jiraOptions JiraOptions, jql string) (*JiraIssueRef, error) {
+
+	bytes, err := j.CustomSearchIssue(jiraOptions, JiraSearchIssueOptions{
+		SearchPattern: jql,
+		MaxResults:    1,
+		Fields:        []string{"key"},
+		Limit:         1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var r struct {
+		Issues []*JiraIssueRef `json:"issues"`
+	}
+	if err := json.Unmarshal(bytes, &r); err != nil {
+		return nil, err
+	}
+	if len(r.Issues) == 0 {
+		return nil, nil
+	}
+	return r.Issues[0], nil
+}
+
+// bump priority only if requested priority is higher than current one
+func (j *Jira) bumpIssuePriority(jiraOptions JiraOptions, key, priority string) (bool, error) {
+
+	u, err := url.Parse(jiraOptions.URL)
+	if err != nil {
+		return false, err
+	}
+	u.Path = path.Join(u.Path, j.apiPath(jiraOptions, "priority"))
+
+	bytes, err := utils.HttpGetRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions))
+	if err != nil {
+		return false, err
+	}
+
+	// priorities are ordered from highest to lowest
+	var priorities []*JiraIssuePriority
+	if err := json.Unmarshal(bytes, &priorities); err != nil {
+		return false, err
+	}
+
+	u, err = url.Parse(jiraOptions.URL)
+	if err != nil {
+		return false, err
+	}
+	u.Path = path.Join(u.Path, j.apiPath(jiraOptions, "issue/%s", key))
+	u.RawQuery = "fields=priority"
+
+	bytes, err = utils.HttpGetRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions))
+	if err != nil {
+		return false, err
+	}
+
+	var issue struct {
+		Fields struct {
+			Priority *JiraIssuePriority `json:"priority"`
+		} `json:"fields"`
+	}
+	if err := json.Unmarshal(bytes, &issue); err != nil {
+		return false, err
+	}
+
+	index := func(name string) int {
+		for i, p := range priorities {
+			if strings.EqualFold(p.Name, name) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	next := index(priority)
+	if next < 0 {
+		return false, fmt.Errorf("jira priority %s is not found", priority)
+	}
+	if issue.Fields.Priority != nil {
+		current := index(issue.Fields.Priority.Name)
+		if current >= 0 && current <= next {
+			return false, nil
+		}
+	}
+
+	req, err := json.Marshal(&JiraIssueUpdate{
+		Fields: &JiraIssueFields{
+			Priority: &JiraIssuePriority{Name: priorities[next].Name},
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	u.RawQuery = ""
+	_, err = utils.HttpPutRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions), req)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (j *Jira) CustomUpsertIssue(jiraOptions JiraOptions, issueOptions JiraIssueOptions, upsertOptions JiraUpsertIssueOptions) ([]byte, error) {
+
+	if utils.IsEmpty(upsertOptions.Fingerprint) {
+		return nil, errors.New("jira issue fingerprint is empty")
+	}
+
+	comment := upsertOptions.Comment
+	if utils.IsEmpty(comment) {
+		comment = issueOptions.Description
+	}
+
+	jql := j.fingerprintJql(issueOptions, upsertOptions)
+	result := &JiraUpsertIssueResult{}
+
+	issue, err := j.findIssue(jiraOptions, fmt.Sprintf("%s AND statusCategory != Done ORDER BY created DESC", jql))
+	if err != nil {
+		return nil, err
+	}
+	if issue != nil {
+		result.Action = "commented"
+	}
+
+	if issue == nil && !utils.IsEmpty(upsertOptions.ReopenTransition) {
+
+		window := upsertOptions.ReopenWindow
+		if utils.IsEmpty(window) {
+			window = "7d"
+		}
+		issue, err = j.findIssue(jiraOptions, fmt.Sprintf("%s AND statusCategory = Done AND resolved >= -%s ORDER BY resolved DESC", jql, window))
+		if err != nil {
+			return nil, err
+		}
+		if issue != nil {
+			_, err = j.CustomTransitionIssue(jiraOptions, JiraIssueOptions{IdOrKey: issue.Key}, JiraTransitionIssueOptions{
+				To: upsertOptions.ReopenTransition,
+			})
+			if err != nil {
+				return nil, err
+			}
+			result.Action = "reopened"
+		}
+	}
+
+	if issue == nil {
+
+		fields := make(map[string]interface{})
+		if utils.IsEmpty(upsertOptions.FingerprintField) {
+			issueOptions.Labels = append(issueOptions.Labels, upsertOptions.Fingerprint)
+		} else {
+			fields[upsertOptions.FingerprintField] = upsertOptions.Fingerprint
+		}
+
+		bytes, err := j.createIssue(jiraOptions, issueOptions, fields)
+		if err != nil {
+			return nil, err
+		}
+
+		issue = &JiraIssueRef{}
+		if err := json.Unmarshal(bytes, issue); err != nil {
+			return nil, err
+		}
+		result.Action = "created"
+		result.ID = issue.ID
+		result.Key = issue.Key
+		return common.JsonMarshal(result)
+	}
+
+	result.ID = issue.ID
+	result.Key = issue.Key
+
+	if !utils.IsEmpty(comment) {
+		_, err = j.CustomAddIssueComment(jiraOptions, JiraIssueOptions{IdOrKey: issue.Key}, JiraAddIssueCommentOptions{Body: comment})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if upsertOptions.BumpPriority && !utils.IsEmpty(issueOptions.Priority) {
+		result.PriorityBumped, err = j.bumpIssuePriority(jiraOptions, issue.Key, issueOptions.Priority)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return common.JsonMarshal(result)
+}
+
+func (j *Jira) UpsertIssue(issueOptions JiraIssueOptions, upsertOptions JiraUpsertIssueOptions) ([]byte, error) {
+	return j.CustomUpsertIssue(j.options, issueOptions, upsertOptions)
+}
+
+func (j *Jira) CustomGetIssue(jiraOptions JiraOptions, issueOptions JiraIssueOptions, getOptions JiraGetIssueOptions) ([]byte, error) {
+
+	u, err := url.Parse(jiraOptions.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, j.apiPath(jiraOptions, "issue/%s", issueOptions.IdOrKey))
+
+	params := make(url.Values)
+	fields := common.RemoveEmptyStrings(getOptions.Fields)
+	if len(fields) > 0 {
+		params.Add("fields", strings.Join(fields, ","))
+	}
+	expand := common.RemoveEmptyStrings(getOptions.Expand)
+	if len(expand) > 0 {
+		params.Add("expand", strings.Join(expand, ","))
+	}
+	u.RawQuery = params.Encode()
+
+	return utils.HttpGetRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions))
+}
+
+func (j *Jira) GetIssue(issueOptions JiraIssueOptions, getOptions JiraGetIssueOptions) ([]byte, error) {
+	return j.CustomGetIssue(j.options, issueOptions, getOptions)
+}
+
+// link type can be set by name (Blocks), outward (blocks) or inward (is blocked by) description
+func (j *Jira) findIssueLinkType(jiraOptions JiraOptions, name string) (*JiraIssueLinkType, bool, error) {
+
+	u, err := url.Parse(jiraOptions.URL)
+	if err != nil {
+		return nil, false, err
+	}
+	u.Path = path.Join(u.Path, j.apiPath(jiraOptions, "issueLinkType"))
+
+	bytes, err := utils.HttpGetRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions))
+	if err != nil {
+		return nil, false, err
+	}
+
+	var r struct {
+		IssueLinkTypes []*JiraIssueLinkType `json:"issueLinkTypes"`
+	}
+	if err := json.Unmarshal(bytes, &r); err != nil {
+		return nil, false, err
+	}
+
+	var available []string
+	for _, t := range r.IssueLinkTypes {
+		if strings.EqualFold(t.Name, name) || strings.EqualFold(t.Outward, name) {
+			return t, false, nil
+		}
+		if strings.EqualFold(t.Inward, name) {
+			return t, true, nil
+		}
+		available = append(available, fmt.Sprintf("%s (%s / %s)", t.Name, t.Outward, t.Inward))
+	}
+	return nil, false, fmt.Errorf("jira issue link type %s is not found, available types: %s", name, strings.Join(available, ", "))
+}
+
+func (j *Jira) CustomLinkIssue(jiraOptions JiraOptions, issueOptions JiraIssueOptions, linkOptions JiraLinkIssueOptions) ([]byte, error) {
+
+	if utils.IsEmpty(linkOptions.To) {
+		return nil, errors.New("jira issue to link is empty")
+	}
+
+	linkType, inward, err := j.findIssueLinkType(jiraOptions, linkOptions.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	// issue <outward> to, e.g. ABC-1 blocks ABC-2
+	link := &JiraIssueLink{
+		Type:         &JiraIssueLinkType{Name: linkType.Name},
+		InwardIssue:  &JiraIssueRef{Key: issueOptions.IdOrKey},
+		OutwardIssue: &JiraIssueRef{Key: linkOptions.To},
+	}
+	if inward {
+		link.InwardIssue, link.OutwardIssue = link.OutwardIssue, link.InwardIssue
+	}
+	if !utils.IsEmpty(linkOptions.Comment) {
+		link.Comment = &JiraIssueAddComment{Body: j.richText(jiraOptions, linkOptions.Comment)}
+	}
+
+	req, err := json.Marshal(link)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(jiraOptions.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, j.apiPath(jiraOptions, "issueLink"))
+
+	_, c, err := utils.HttpPostRawOutCode(j.client, u.String(), "application/json", j.getAuth(jiraOptions), req)
+	if err != nil {
+		return nil, err
+	}
+	return common.JsonMarshal(&OutputCode{Code: c})
+}
+
+func (j *Jira) LinkIssue(issueOptions JiraIssueOptions, linkOptions JiraLinkIssueOptions) ([]byte, error) {
+	return j.CustomLinkIssue(j.options, issueOptions, linkOptions)
+}
+
+func (j *Jira) CustomAddIssueWatcher(jiraOptions JiraOptions, issueOptions JiraIssueOptions, watcherOptions JiraAddIssueWatcherOptions) ([]byte, error) {
+
+	if utils.IsEmpty(watcherOptions.User) {
+		return nil, errors.New("jira issue watcher is empty")
+	}
+
+	// body is a plain json string with user name or account ID
+	req, err := json.Marshal(watcherOptions.User)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(jiraOptions.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, j.apiPath(jiraOptions, "issue/%s/watchers", issueOptions.IdOrKey))
+
+	_, c, err := utils.HttpPostRawOutCode(j.client, u.String(), "application/json", j.getAuth(jiraOptions), req)
+	if err != nil {
+		return nil, err
+	}
+	return common.JsonMarshal(&OutputCode{Code: c})
+}
+
+func (j *Jira) AddIssueWatcher(issueOptions JiraIssueOptions, watcherOptions JiraAddIssueWatcherOptions) ([]byte, error) {
+	return j.CustomAddIssueWatcher(j.options, issueOptions, watcherOptions)
+}
+
+func (j *Jira) CustomAddIssueWorklog(jiraOptions JiraOptions, issueOptions JiraIssueOptions, worklogOptions JiraAddIssueWorklogOptions) ([]byte, error) {
+
+	if utils.IsEmpty(worklogOptions.TimeSpent) {
+		return nil, errors.New("jira issue worklog time spent is empty")
+	}
+
+	worklog := &JiraIssueWorklog{
+		TimeSpent: worklogOptions.TimeSpent,
+		Comment:   j.richText(jiraOptions, worklogOptions.Comment),
+	}
+
+	if !utils.IsEmpty(worklogOptions.Started) {
+		t, err := time.Parse(time.RFC3339, worklogOptions.Started)
+		if err != nil {
+			return nil, err
+		}
+		worklog.Started = t.Format("2006-01-02T15:04:05.000-0700")
+	}
+
+	req, err := json.Marshal(worklog)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(jiraOptions.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, j.apiPath(jiraOptions, "issue/%s/worklog", issueOptions.IdOrKey))
+	return utils.HttpPostRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions), req)
+}
+
+func (j *Jira) AddIssueWorklog(issueOptions JiraIssueOptions, worklogOptions JiraAddIssueWorklogOptions) ([]byte, error) {
+	return j.CustomAddIssueWorklog(j.options, issueOptions, worklogOptions)
+}
+
+func (j *Jira) CustomAddIssueRemoteLink(jiraOptions JiraOptions, issueOptions JiraIssueOptions, remoteLinkOptions JiraAddIssueRemoteLinkOptions) ([]byte, error) {
+
+	if utils.IsEmpty(remoteLinkOptions.URL) {
+		return nil, errors.New("jira issue remote link url is empty")
+	}
+
+	title := remoteLinkOptions.Title
+	if utils.IsEmpty(title) {
+		title = remoteLinkOptions.URL
+	}
+
+	// the same global ID updates existing link instead of creating a new one
+	globalID := remoteLinkOptions.GlobalID
+	if utils.IsEmpty(globalID) {
+		globalID = remoteLinkOptions.URL
+	}
+
+	link := &JiraIssueRemoteLink{
+		GlobalID:     globalID,
+		Relationship: remoteLinkOptions.Relationship,
+		Object: &JiraIssueRemoteLinkObject{
+			URL:     remoteLinkOptions.URL,
+			Title:   title,
+			Summary: remoteLinkOptions.Summary,
+		},
+	}
+	if !utils.IsEmpty(remoteLinkOptions.Icon) {
+		link.Object.Icon = &JiraIssueRemoteLinkIcon{
+			URL16x16: remoteLinkOptions.Icon,
+			Title:    title,
+		}
+	}
+
+	req, err := json.Marshal(link)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(jiraOptions.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, j.apiPath(jiraOptions, "issue/%s/remotelink", issueOptions.IdOrKey))
+	return utils.HttpPostRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions), req)
+}
+
+func (j *Jira) AddIssueRemoteLink(issueOptions JiraIssueOptions, remoteLinkOptions JiraAddIssueRemoteLinkOptions) ([]byte, error) {
+	return j.CustomAddIssueRemoteLink(j.options, issueOptions, remoteLinkOptions)
+}
+
+func (j *Jira) bulkCreateChunk(jiraOptions JiraOptions, issues []*JiraBulkIssue) []*JiraBulkCreateIssueResult {
+
+	results := make([]*JiraBulkCreateIssueResult, len(issues))
+	fail := func(err string) []*JiraBulkCreateIssueResult {
+		for i, issue := range issues {
+			results[i] = &JiraBulkCreateIssueResult{Row: issue.Row, Record: issue.Record, Error: err}
+		}
+		return results
+	}
+
+	var updates []interface{}
+	for _, issue := range issues {
+		fields := issue.Fields
+		if d, ok := fields["description"].(string); ok {
+			fields["description"] = j.richText(jiraOptions, d)
+		}
+		updates = append(updates, map[string]interface{}{"fields": fields})
+	}
+
+	req, err := json.Marshal(map[string]interface{}{"issueUpdates": updates})
+	if err != nil {
+		return fail(err.Error())
+	}
+
+	u, err := url.Parse(jiraOptions.URL)
+	if err != nil {
+		return fail(err.Error())
+	}
+	u.Path = path.Join(u.Path, j.apiPath(jiraOptions, "issue/bulk"))
+
+	headers := make(map[string]string)
+	headers["Content-Type"] = "application/json"
+	headers["Authorization"] = j.getAuth(jiraOptions)
+
+	// partially failed request returns 400 with created issues and errors per element
+	body, _, err := utils.HttpRequestRawWithHeadersOutCode(j.client, http.MethodPost, u.String(), headers, req)
+
+	var r struct {
+		Issues []*JiraIssueRef `json:"issues"`
+		Errors []struct {
+			FailedElementNumber int `json:"failedElementNumber"`
+			ElementErrors       struct {
+				ErrorMessages []string          `json:"errorMessages"`
+				Errors        map[string]string `json:"errors"`
+			} `json:"elementErrors"`
+		} `json:"errors"`
+	}
+	if e := json.Unmarshal(body, &r); e != nil || (err != nil && len(r.Errors) == 0) {
+		if err == nil {
+			err = e
+		}
+		return fail(err.Error())
+	}
+
+	for _, e := range r.Errors {
+		if e.FailedElementNumber < 0 || e.FailedElementNumber >= len(issues) {
+			continue
+		}
+		msgs := e.ElementErrors.ErrorMessages
+		for k, v := range e.ElementErrors.Errors {
+			msgs = append(msgs, fmt.Sprintf("%s: %s", k, v))
+		}
+		results[e.FailedElementNumber] = &JiraBulkCreateIssueResult{
+			Row:    issues[e.FailedElementNumber].Row,
+			Record: issues[e.FailedElementNumber].Record,
+			Error:  strings.Join(msgs, "; "),
+		}
+	}
+
+	// created issues are returned in order of successful elements
+	n := 0
+	for i, issue := range issues {
+		if results[i] != nil {
+			continue
+		}
+		results[i] = &JiraBulkCreateIssueResult{Row: issue.Row, Record: issue.Record}
+		if n < len(r.Issues) {
+			results[i].ID = r.Issues[n].ID
+			results[i].Key = r.Issues[n].Key
+			n++
+		} else {
+			results[i].Error = "no result returned"
+		}
+	}
+	return results
+}
+
+// CustomBulkCreateIssues creates issues in chunks, fn is called after every chunk to be able to save progress
+func (j *Jira) CustomBulkCreateIssues(jiraOptions JiraOptions, bulkOptions JiraBulkCreateIssueOptions, issues []*JiraBulkIssue, fn func(results []*JiraBulkCreateIssueResult) error) ([]*JiraBulkCreateIssueResult, error) {
+
+	size := bulkOptions.ChunkSize
+	if size <= 0 || size > 50 {
+		size = 50
+	}
+
+	var results []*JiraBulkCreateIssueResult
+	for i := 0; i < len(issues); i += size {
+
+		end := i + size
+		if end > len(issues) {
+			end = len(issues)
+		}
+
+		chunk := j.bulkCreateChunk(jiraOptions, issues[i:end])
+		results = append(results, chunk...)
+		if fn != nil {
+			if err := fn(chunk); err != nil {
+				return results, err
+			}
+		}
+	}
+	return results, nil
+}
+
+func (j *Jira) BulkCreateIssues(bulkOptions JiraBulkCreateIssueOptions, issues []*JiraBulkIssue, fn func(results []*JiraBulkCreateIssueResult) error) ([]*JiraBulkCreateIssueResult, error) {
+	return j.CustomBulkCreateIssues(j.options, bulkOptions, issues, fn)
+}
+
+type JiraAssetsSearchPage struct {
+	ObjectEntries        []json.RawMessage `json:"objectEntries"`
+	ObjectTypeAttributes []json.RawMessage `json:"objectTypeAttributes"`
+	TotalFilterCount     int               `json:"totalFilterCount"`
+}
+
+func (j *Jira) searchAssetsPage(jiraOptions JiraOptions, search JiraSearchAssetsOptions, page int) (*JiraAssetsSearchPage, error) {
+
+	params := make(url.Values)
+	params.Add("qlQuery", search.SearchPattern)
+	params.Add("resultPerPage", strconv.Itoa(search.ResultPerPage))
+	params.Add("page", strconv.Itoa(page))
+
+	u, err := url.Parse(jiraOptions.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, "/rest/insight/1.0/aql/objects")
+	u.RawQuery = params.Encode()
+
+	headers := make(map[string]string)
+	headers["Content-Type"] = "application/json"
+	headers["Authorization"] = j.getAuth(jiraOptions)
+
+	body, _, err := utils.HttpRequestRawWithHeadersOutCode(j.client, http.MethodGet, u.String(), headers, nil)
+	if err != nil {
+		return nil, responseError(body, err)
+	}
+
+	var r JiraAssetsSearchPage
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// jiraAssetFlatten converts object into map of attribute name to display value, list for multiple values
+func jiraAssetFlatten(object *JiraAssetObject, names map[int]string) map[string]interface{} {
+
+	m := make(map[string]interface{})
+	for _, a := range object.Attributes {
+
+		name, ok := names[a.ObjectTypeAttributeId]
+		if !ok {
+			name = strconv.Itoa(a.ObjectTypeAttributeId)
+		}
+
+		var values []interface{}
+		for _, v := range a.ObjectAttributeValues {
+			value := v.DisplayValue
+			if value == nil {
+				value = v.Value
+			}
+			values = append(values, value)
+		}
+
+		switch len(values) {
+		case 0:
+			m[name] = nil
+		case 1:
+			m[name] = values[0]
+		default:
+			m[name] = values
+		}
+	}
+	return m
+}
+
+// CustomSearchAssets pages through AQL results until total count is reached or page is not full
+func (j *Jira) CustomSearchAssets(jiraOptions JiraOptions, search JiraSearchAssetsOptions) ([]byte, error) {
+
+	if search.ResultPerPage <= 0 {
+		search.ResultPerPage = 50
+	}
+
+	objects := []json.RawMessage{}
+	attributes := []json.RawMessage{}
+	total := 0
+
+	for page := 1; ; page++ {
+
+		r, err := j.searchAssetsPage(jiraOptions, search, page)
+		if err != nil {
+			return nil, err
+		}
+		if page == 1 {
+			total = r.TotalFilterCount
+			if r.ObjectTypeAttributes != nil {
+				attributes = r.ObjectTypeAttributes
+			}
+		}
+		objects = append(objects, r.ObjectEntries...)
+
+		if search.Limit > 0 && len(objects) >= search.Limit {
+			objects = objects[:search.Limit]
+			break
+		}
+		if len(r.ObjectEntries) < search.ResultPerPage || len(objects) >= total {
+			break
+		}
+	}
+
+	result := map[string]interface{}{
+		"total":      total,
+		"objects":    objects,
+		"attributes": attributes,
+	}
+	if !search.Flatten {
+		return json.Marshal(result)
+	}
+
+	names := make(map[int]string)
+	for _, raw := range attributes {
+		var a JiraAssetTypeAttribute
+		if err := json.Unmarshal(raw, &a); err != nil {
+			return nil, err
+		}
+		names[a.ID] = a.Name
+	}
+
+	flat := []map[string]interface{}{}
+	for _, raw := range objects {
+		var object JiraAssetObject
+		if err := json.Unmarshal(raw, &object); err != nil {
+			return nil, err
+		}
+		flat = append(flat, jiraAssetFlatten(&object, names))
+	}
+	result["objects"] = flat
+	return json.Marshal(result)
+}
+
+func (j *Jira) SearchAssets(options JiraSearchAssetsOptions) ([]byte, error) {
+	return j.CustomSearchAssets(j.options, options)
+}
+
+func (j *Jira) getAsset(jiraOptions JiraOptions, objectId string) ([]byte, error) {
+
+	if utils.IsEmpty(objectId) {
+		return nil, errors.New("jira asset object id is empty")
+	}
+
+	u, err := url.Parse(jiraOptions.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, jiraAssetsPath("object/%s", url.PathEscape(objectId)))
+	return utils.HttpGetRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions))
+}
+
+func (j *Jira) CustomGetAsset(jiraOptions JiraOptions, getOptions JiraGetAssetOptions) ([]byte, error) {
+	return j.getAsset(jiraOptions, getOptions.ObjectId)
+}
+
+func (j *Jira) GetAsset(getOptions JiraGetAssetOptions) ([]byte, error) {
+	return j.CustomGetAsset(j.options, getOptions)
+}
+
+func (j *Jira) updateAsset(jiraOptions JiraOptions, objectId string, objectTypeId int, attributes []JiraAssetAttribute) ([]byte, error) {
+
+	req, err := json.Marshal(&JiraAsset{
+		ObjectTypeId: objectTypeId,
+		Attributes:   attributes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(jiraOptions.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, jiraAssetsPath("object/%s", url.PathEscape(objectId)))
+	return utils.HttpPutRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions), req)
+}
+
+func (j *Jira) CustomUpdateAsset(jiraOptions JiraOptions, updateOptions JiraUpdateAssetOptions) ([]byte, error) {
+
+	objectTypeId := updateOptions.ObjectTypeId
+	if objectTypeId == 0 {
+
+		bytes, err := j.getAsset(jiraOptions, updateOptions.ObjectId)
+		if err != nil {
+			return nil, err
+		}
+		var object JiraAssetObject
+		if err := json.Unmarshal(bytes, &object); err != nil {
+			return nil, err
+		}
+		if object.ObjectType != nil {
+			objectTypeId = object.ObjectType.ID
+		}
+	}
+
+	attributes, err := j.assetAttributes(jiraOptions, objectTypeId, updateOptions.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	return j.updateAsset(jiraOptions, updateOptions.ObjectId, objectTypeId, attributes)
+}
+
+func (j *Jira) UpdateAsset(updateOptions JiraUpdateAssetOptions) ([]byte, error) {
+	return j.CustomUpdateAsset(j.options, updateOptions)
+}
+
+func (j *Jira) CustomDeleteAsset(jiraOptions JiraOptions, deleteOptions JiraDeleteAssetOptions) ([]byte, error) {
+
+	if utils.IsEmpty(deleteOptions.ObjectId) {
+		return nil, errors.New("jira asset object id is empty")
+	}
+
+	u, err := url.Parse(jiraOptions.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, jiraAssetsPath("object/%s", url.PathEscape(deleteOptions.ObjectId)))
+	return utils.HttpDeleteRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions), nil)
+}
+
+func (j *Jira) DeleteAsset(deleteOptions JiraDeleteAssetOptions) ([]byte, error) {
+	return j.CustomDeleteAsset(j.options, deleteOptions)
+}
+
+func (j *Jira) findAssets(jiraOptions JiraOptions, aql string) ([]*JiraAssetObject, error) {
+
+	params := make(url.Values)
+	params.Add("qlQuery", aql)
+	params.Add("resultPerPage", "2")
+
+	u, err := url.Parse(jiraOptions.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, "/rest/insight/1.0/aql/objects")
+	u.RawQuery = params.Encode()
+
+	bytes, err := utils.HttpGetRaw(j.client, u.String(), "application/json", j.getAuth(jiraOptions))
+	if err != nil {
+		return nil, err
+	}
+
+	var r struct {
+		ObjectEntries []*JiraAssetObject `json:"objectEntries"`
+	}
+	if err := json.Unmarshal(bytes, &r); err != nil {
+		return nil, err
+	}
+	return r.ObjectEntries, nil
+}
+
+// jiraAssetChanged compares desired attributes with existing object ones, references are compared by object key
+func jiraAssetChanged(object *JiraAssetObject, attributes []JiraAssetAttribute) bool {
+
+	existing := make(map[int][]string)
+	for _, a := range object.Attributes {
+		for _, v := range a.ObjectAttributeValues {
+			value := jiraAssetValue(v.Value)
+			if v.ReferencedObject != nil && !utils.IsEmpty(v.ReferencedObject.ObjectKey) {
+				value = v.ReferencedObject.ObjectKey
+			}
+			existing[a.ObjectTypeAttributeId] = append(existing[a.ObjectTypeAttributeId], value)
+		}
+	}
+
+	for _, a := range attributes {
+		var desired []string
+		for _, v := range a.ObjectAttributeValues {
+			desired = append(desired, v.Value)
+		}
+		current := existing[a.ObjectTypeAttributeId]
+		if len(current) != len(desired) {
+			return true
+		}
+		sort.Strings(current)
+		sort.Strings(desired)
+		for i := range desired {
+			if current[i] != desired[i] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (j *Jira) syncAsset(jiraOptions JiraOptions, syncOptions JiraSyncAssetsOptions, schema []*JiraAssetTypeAttribute, values map[string]interface{}) *JiraSyncAssetResult {
+
+	result := &JiraSyncAssetResult{}
+	fail := func(err error) *JiraSyncAssetResult {
+		result.Action = "failed"
+		result.Error = err.Error()
+		return result
+	}
+
+	var key interface{}
+	for k, v := range values {
+		if strings.EqualFold(k, syncOptions.KeyAttribute) {
+			key = v
+		}
+	}
+	result.Key = jiraAssetValue(key)
+	if utils.IsEmpty(result.Key) {
+		return fail(fmt.Errorf("jira asset key attribute %s is empty", syncOptions.KeyAttribute))
+	}
+
+	attributes, err := jiraAssetBuildAttributes(schema, syncOptions.ObjectTypeId, values)
+	if err != nil {
+		return fail(err)
+	}
+
+	aql := fmt.Sprintf("objectTypeId = %d AND %s = %s", syncOptions.ObjectTypeId, jiraAqlQuote(syncOptions.KeyAttribute), jiraAqlQuote(result.Key))
+	objects, err := j.findAssets(jiraOptions, aql)
+	if err != nil {
+		return fail(err)
+	}
+
+	var bytes []byte
+	switch len(objects) {
+	case 0:
+		result.Action = "created"
+		bytes, err = j.createAsset(jiraOptions, syncOptions.ObjectSchemeId, syncOptions.ObjectTypeId, attributes)
+	case 1:
+		result.ID = objects[0].ID
+		result.ObjectKey = objects[0].ObjectKey
+		if !jiraAssetChanged(objects[0], attributes) {
+			result.Action = "unchanged"
+			return result
+		}
+		result.Action = "updated"
+		bytes, err = j.updateAsset(jiraOptions, strconv.Itoa(objects[0].ID), syncOptions.ObjectTypeId, attributes)
+	default:
+		return fail(fmt.Errorf("jira asset key %s matches more than one object", result.Key))
+	}
+	if err != nil {
+		return fail(err)
+	}
+
+	var object JiraAssetObject
+	if err := json.Unmarshal(bytes, &object); err == nil && object.ID > 0 {
+		result.ID = object.ID
+		result.ObjectKey = object.ObjectKey
+	}
+	return result
+}
+
+// CustomSyncAssets upserts objects matching them by key attribute, failed objects don't stop sync
+func (j *Jira) CustomSyncAssets(jiraOptions JiraOptions, syncOptions JiraSyncAssetsOptions) ([]byte, error) {
+
+	if utils.IsEmpty(syncOptions.KeyAttribute) {
+		return nil, errors.New("jira assets sync key attribute is empty")
+	}
+	if syncOptions.ObjectTypeId == 0 {
+		return nil, errors.New("jira asset object type id is empty")
+	}
+
+	bytes, err := utils.Content(syncOptions.Objects)
+	if err != nil {
+		return nil, err
+	}
+	var objects []map[string]interface{}
+	if err := json.Unmarshal(bytes, &objects); err != nil {
+		return nil, err
+	}
+
+	schema, err := j.getAssetTypeAttributes(jiraOptions, syncOptions.ObjectTypeId)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := jiraAssetTypeAttributeID(schema, syncOptions.ObjectTypeId, syncOptions.KeyAttribute); err != nil {
+		return nil, err
+	}
+
+	results := []*JiraSyncAssetResult{}
+	for _, values := range objects {
+		results = append(results, j.syncAsset(jiraOptions, syncOptions, schema, values))
+	}
+	return json.Marshal(results)
+}
+
+func (j *Jira) SyncAssets(syncOptions JiraSyncAssetsOptions) ([]byte, error) {
+	return j.CustomSyncAssets(j.options, syncOptions)
 }
 
 func NewJira(options JiraOptions) *Jira {
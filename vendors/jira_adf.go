@@ -0,0 +1,336 @@
+package vendors
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Atlassian Document Format used by Jira Cloud REST v3 for rich text fields
+
+type JiraADFMark struct {
+	Type  string                 `json:"type"`
+	Attrs map[string]interface{} `json:"attrs,omitempty"`
+}
+
+type JiraADFNode struct {
+	Version int                    `json:"version,omitempty"`
+	Type    string                 `json:"type"`
+	Attrs   map[string]interface{} `json:"attrs,omitempty"`
+	Content []*JiraADFNode         `json:"content,omitempty"`
+	Text    string                 `json:"text,omitempty"`
+	Marks   []*JiraADFMark         `json:"marks,omitempty"`
+}
+
+var (
+	jiraADFHeading  = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	jiraADFBullet   = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	jiraADFOrdered  = regexp.MustCompile(`^(\s*)\d+[.)]\s+(.*)$`)
+	jiraADFRule     = regexp.MustCompile(`^\s*(-\s*){3,}$|^\s*(\*\s*){3,}$|^\s*(_\s*){3,}$`)
+	jiraADFTableSep = regexp.MustCompile(`^\s*\|?(\s*:?-+:?\s*\|)+\s*:?-*:?\s*\|?\s*$`)
+	jiraADFLink     = regexp.MustCompile(`^\[([^\]]*)\]\(([^)\s]+)\)`)
+	jiraADFMention  = regexp.MustCompile(`^\[~accountid:([^\]]+)\]`)
+	jiraADFEmphasis = map[string]string{"**": "strong", "__": "strong", "~~": "strike", "*": "em", "_": "em"}
+)
+
+func jiraADFText(text string, marks []*JiraADFMark) *JiraADFNode {
+
+	node := &JiraADFNode{Type: "text", Text: text}
+	if len(marks) > 0 {
+		node.Marks = append([]*JiraADFMark{}, marks...)
+	}
+	return node
+}
+
+func jiraADFWithMark(marks []*JiraADFMark, mark *JiraADFMark) []*JiraADFMark {
+	return append(append([]*JiraADFMark{}, marks...), mark)
+}
+
+// jiraADFInline converts inline markdown: **strong**, *em*, ~~strike~~, `code`, [text](url) and [~accountid:ID] mentions
+func jiraADFInline(s string, marks []*JiraADFMark) []*JiraADFNode {
+
+	var nodes []*JiraADFNode
+	var plain strings.Builder
+
+	flush := func() {
+		if plain.Len() > 0 {
+			nodes = append(nodes, jiraADFText(plain.String(), marks))
+			plain.Reset()
+		}
+	}
+
+	for i := 0; i < len(s); {
+
+		rest := s[i:]
+
+		if strings.HasPrefix(rest, "`") {
+			if end := strings.Index(rest[1:], "`"); end > 0 {
+				flush()
+				// code mark can be combined with link mark only
+				var codeMarks []*JiraADFMark
+				for _, m := range marks {
+					if m.Type == "link" {
+						codeMarks = append(codeMarks, m)
+					}
+				}
+				nodes = append(nodes, jiraADFText(rest[1:end+1], jiraADFWithMark(codeMarks, &JiraADFMark{Type: "code"})))
+				i += end + 2
+				continue
+			}
+		}
+
+		if m := jiraADFMention.FindStringSubmatch(rest); m != nil {
+			flush()
+			nodes = append(nodes, &JiraADFNode{Type: "mention", Attrs: map[string]interface{}{"id": m[1]}})
+			i += len(m[0])
+			continue
+		}
+
+		if m := jiraADFLink.FindStringSubmatch(rest); m != nil {
+			flush()
+			text := m[1]
+			if text == "" {
+				text = m[2]
+			}
+			nodes = append(nodes, jiraADFInline(text, jiraADFWithMark(marks, &JiraADFMark{Type: "link", Attrs: map[string]interface{}{"href": m[2]}}))...)
+			i += len(m[0])
+			continue
+		}
+
+		matched := false
+		for _, d := range []string{"**", "__", "~~", "*", "_"} {
+
+			if !strings.HasPrefix(rest, d) || len(rest) <= len(d) || rest[len(d)] == ' ' {
+				continue
+			}
+			// intraword underscores like snake_case are not emphasis
+			if d[0] == '_' && i > 0 && (unicode.IsLetter(rune(s[i-1])) || unicode.IsDigit(rune(s[i-1]))) {
+				continue
+			}
+			end := strings.Index(rest[len(d):], d)
+			if end <= 0 || rest[len(d)+end-1] == ' ' {
+				continue
+			}
+			flush()
+			inner := rest[len(d) : len(d)+end]
+			nodes = append(nodes, jiraADFInline(inner, jiraADFWithMark(marks, &JiraADFMark{Type: jiraADFEmphasis[d]}))...)
+			i += len(d)*2 + end
+			matched = true
+			break
+		}
+		if matched {
+			continue
+		}
+
+		plain.WriteByte(s[i])
+		i++
+	}
+	flush()
+	return nodes
+}
+
+func jiraADFParagraph(lines []string) *JiraADFNode {
+
+	p := &JiraADFNode{Type: "paragraph"}
+	for i, line := range lines {
+		if i > 0 {
+			p.Content = append(p.Content, &JiraADFNode{Type: "hardBreak"})
+		}
+		p.Content = append(p.Content, jiraADFInline(strings.TrimSpace(line), nil)...)
+	}
+	return p
+}
+
+func jiraADFTableCells(line string) []string {
+
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	line = strings.TrimSuffix(line, "|")
+	cells := strings.Split(line, "|")
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+	return cells
+}
+
+func jiraADFTable(lines []string) *JiraADFNode {
+
+	table := &JiraADFNode{Type: "table"}
+	for i, line := range lines {
+		if i == 1 {
+			continue // separator
+		}
+		cellType := "tableCell"
+		if i == 0 {
+			cellType = "tableHeader"
+		}
+		row := &JiraADFNode{Type: "tableRow"}
+		for _, c := range jiraADFTableCells(line) {
+			row.Content = append(row.Content, &JiraADFNode{
+				Type:    cellType,
+				Content: []*JiraADFNode{{Type: "paragraph", Content: jiraADFInline(c, nil)}},
+			})
+		}
+		table.Content = append(table.Content, row)
+	}
+	return table
+}
+
+func jiraADFListItem(line string) (indent int, ordered bool, text string, ok bool) {
+
+	if m := jiraADFOrdered.FindStringSubmatch(line); m != nil {
+		return len(m[1]), true, m[2], true
+	}
+	if m := jiraADFBullet.FindStringSubmatch(line); m != nil && !jiraADFRule.MatchString(line) {
+		return len(m[1]), false, m[2], true
+	}
+	return 0, false, "", false
+}
+
+// jiraADFList parses list starting at lines[i] with given indent, nested lists are deeper indented items
+func jiraADFList(lines []string, i int) (*JiraADFNode, int) {
+
+	indent, ordered, _, _ := jiraADFListItem(lines[i])
+	list := &JiraADFNode{Type: "bulletList"}
+	if ordered {
+		list.Type = "orderedList"
+	}
+
+	for i < len(lines) {
+
+		ind, ord, text, ok := jiraADFListItem(lines[i])
+		if !ok || ind < indent || (ind == indent && ord != ordered) {
+			break
+		}
+		if ind > indent {
+			if len(list.Content) == 0 {
+				break
+			}
+			var nested *JiraADFNode
+			nested, i = jiraADFList(lines, i)
+			last := list.Content[len(list.Content)-1]
+			last.Content = append(last.Content, nested)
+			continue
+		}
+		list.Content = append(list.Content, &JiraADFNode{
+			Type:    "listItem",
+			Content: []*JiraADFNode{{Type: "paragraph", Content: jiraADFInline(text, nil)}},
+		})
+		i++
+	}
+	return list, i
+}
+
+func jiraADFBlocks(lines []string) []*JiraADFNode {
+
+	var blocks []*JiraADFNode
+	var para []string
+
+	flush := func() {
+		if len(para) > 0 {
+			blocks = append(blocks, jiraADFParagraph(para))
+			para = nil
+		}
+	}
+
+	for i := 0; i < len(lines); {
+
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flush()
+			i++
+
+		case strings.HasPrefix(trimmed, "```"):
+			flush()
+			block := &JiraADFNode{Type: "codeBlock"}
+			if lang := strings.TrimSpace(strings.TrimPrefix(trimmed, "```")); lang != "" {
+				block.Attrs = map[string]interface{}{"language": lang}
+			}
+			var code []string
+			i++
+			for i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```") {
+				code = append(code, lines[i])
+				i++
+			}
+			i++ // closing fence
+			if len(code) > 0 {
+				block.Content = []*JiraADFNode{{Type: "text", Text: strings.Join(code, "\n")}}
+			}
+			blocks = append(blocks, block)
+
+		case jiraADFHeading.MatchString(trimmed):
+			flush()
+			m := jiraADFHeading.FindStringSubmatch(trimmed)
+			blocks = append(blocks, &JiraADFNode{
+				Type:    "heading",
+				Attrs:   map[string]interface{}{"level": len(m[1])},
+				Content: jiraADFInline(m[2], nil),
+			})
+			i++
+
+		case jiraADFRule.MatchString(trimmed):
+			flush()
+			blocks = append(blocks, &JiraADFNode{Type: "rule"})
+			i++
+
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			var quote []string
+			for i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">") {
+				q := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quote = append(quote, strings.TrimPrefix(q, " "))
+				i++
+			}
+			blocks = append(blocks, &JiraADFNode{Type: "blockquote", Content: jiraADFBlocks(quote)})
+
+		case strings.HasPrefix(trimmed, "|") && i+1 < len(lines) && jiraADFTableSep.MatchString(lines[i+1]):
+			flush()
+			var table []string
+			for i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|") {
+				table = append(table, lines[i])
+				i++
+			}
+			blocks = append(blocks, jiraADFTable(table))
+
+		default:
+			if _, _, _, ok := jiraADFListItem(line); ok {
+				flush()
+				var list *JiraADFNode
+				list, i = jiraADFList(lines, i)
+				blocks = append(blocks, list)
+				continue
+			}
+			para = append(para, line)
+			i++
+		}
+	}
+	flush()
+	return blocks
+}
+
+// JiraMarkdownToADF converts markdown to ADF document, text which is already ADF json is returned as is
+func JiraMarkdownToADF(text string) interface{} {
+
+	trimmed := strings.TrimSpace(text)
+	if strings.HasPrefix(trimmed, "{") {
+		var doc map[string]interface{}
+		if err := json.Unmarshal([]byte(trimmed), &doc); err == nil && doc["type"] == "doc" {
+			return doc
+		}
+	}
+
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	content := jiraADFBlocks(strings.Split(text, "\n"))
+	if content == nil {
+		content = []*JiraADFNode{}
+	}
+	return &JiraADFNode{
+		Version: 1,
+		Type:    "doc",
+		Content: content,
+	}
+}
@@ -0,0 +1,251 @@
+package vendors
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/devopsext/utils"
+)
+
+// Bulk records read from CSV or NDJSON, state file keeps created issues by record key to resume after failures
+
+type jiraBulkRecord struct {
+	Row  int
+	Key  string
+	Data map[string]interface{}
+}
+
+type jiraBulkState struct {
+	done map[string]*JiraBulkCreateIssueResult
+	file *os.File
+}
+
+func jiraReadBulkData(input, format string) ([]map[string]interface{}, error) {
+
+	f, err := os.Open(input)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if utils.IsEmpty(format) {
+		format = "ndjson"
+		if strings.EqualFold(filepath.Ext(input), ".csv") {
+			format = "csv"
+		}
+	}
+
+	var records []map[string]interface{}
+	switch strings.ToLower(format) {
+	case "csv":
+		rows, err := csv.NewReader(f).ReadAll()
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == 0 {
+			return records, nil
+		}
+		header := rows[0]
+		for _, row := range rows[1:] {
+			record := make(map[string]interface{})
+			for i, v := range row {
+				if i < len(header) {
+					record[header[i]] = v
+				}
+			}
+			records = append(records, record)
+		}
+	case "ndjson":
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			record := make(map[string]interface{})
+			if err := json.Unmarshal([]byte(line), &record); err != nil {
+				return nil, fmt.Errorf("jira bulk record %d: %s", len(records)+1, err)
+			}
+			records = append(records, record)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("jira bulk format %s is not supported", format)
+	}
+	return records, nil
+}
+
+// jiraReadBulkRecords keys records by id field or by content hash, so rows can be inserted or removed between runs
+func jiraReadBulkRecords(bulkOptions JiraBulkCreateIssueOptions) ([]*jiraBulkRecord, error) {
+
+	data, err := jiraReadBulkData(bulkOptions.Input, bulkOptions.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]int)
+	var records []*jiraBulkRecord
+	for i, d := range data {
+
+		var key string
+		if !utils.IsEmpty(bulkOptions.IDField) {
+			id, ok := d[bulkOptions.IDField]
+			if !ok || utils.IsEmpty(fmt.Sprintf("%v", id)) {
+				return nil, fmt.Errorf("jira bulk record %d has no %s", i+1, bulkOptions.IDField)
+			}
+			key = fmt.Sprintf("%v", id)
+		} else {
+			b, err := json.Marshal(d)
+			if err != nil {
+				return nil, err
+			}
+			sum := sha256.Sum256(b)
+			key = hex.EncodeToString(sum[:16])
+		}
+
+		// same records are told apart by occurrence
+		seen[key]++
+		if seen[key] > 1 {
+			key = fmt.Sprintf("%s-%d", key, seen[key])
+		}
+		records = append(records, &jiraBulkRecord{Row: i + 1, Key: key, Data: d})
+	}
+	return records, nil
+}
+
+func newJiraBulkState(state string) (*jiraBulkState, error) {
+
+	s := &jiraBulkState{done: make(map[string]*JiraBulkCreateIssueResult)}
+	if utils.IsEmpty(state) {
+		return s, nil
+	}
+
+	if utils.FileExists(state) {
+		b, err := os.ReadFile(state)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range strings.Split(string(b), "\n") {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			r := &JiraBulkCreateIssueResult{}
+			if err := json.Unmarshal([]byte(line), r); err != nil {
+				return nil, err
+			}
+			if !utils.IsEmpty(r.Key) && !utils.IsEmpty(r.Record) {
+				s.done[r.Record] = r
+			}
+		}
+	}
+
+	f, err := os.OpenFile(state, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	s.file = f
+	return s, nil
+}
+
+func (s *jiraBulkState) save(results []*JiraBulkCreateIssueResult) error {
+
+	if s.file == nil {
+		return nil
+	}
+	for _, r := range results {
+		b, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		if _, err := s.file.Write(append(b, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *jiraBulkState) close() {
+	if s.file != nil {
+		s.file.Close()
+	}
+}
+
+// CustomBulkCreateIssuesFromInput renders every record into issue fields json, records created in previous runs are skipped
+func (j *Jira) CustomBulkCreateIssuesFromInput(jiraOptions JiraOptions, bulkOptions JiraBulkCreateIssueOptions, render func(record interface{}) ([]byte, error)) ([]*JiraBulkCreateIssueResult, error) {
+
+	records, err := jiraReadBulkRecords(bulkOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := newJiraBulkState(bulkOptions.State)
+	if err != nil {
+		return nil, err
+	}
+	defer state.close()
+
+	results := make(map[int]*JiraBulkCreateIssueResult)
+	var issues []*JiraBulkIssue
+
+	for _, record := range records {
+
+		if r, ok := state.done[record.Key]; ok {
+			done := *r
+			done.Row = record.Row
+			results[record.Row] = &done
+			continue
+		}
+
+		b, err := render(record.Data)
+		if err != nil {
+			results[record.Row] = &JiraBulkCreateIssueResult{Row: record.Row, Record: record.Key, Error: err.Error()}
+			continue
+		}
+
+		fields := make(map[string]interface{})
+		if err := json.Unmarshal(b, &fields); err != nil {
+			results[record.Row] = &JiraBulkCreateIssueResult{Row: record.Row, Record: record.Key, Error: fmt.Sprintf("rendered fields are not json: %s", err)}
+			continue
+		}
+		if fields == nil {
+			results[record.Row] = &JiraBulkCreateIssueResult{Row: record.Row, Record: record.Key, Error: "rendered fields are empty"}
+			continue
+		}
+		if f, ok := fields["fields"].(map[string]interface{}); ok {
+			fields = f
+		}
+		issues = append(issues, &JiraBulkIssue{Row: record.Row, Record: record.Key, Fields: fields})
+	}
+
+	_, err = j.CustomBulkCreateIssues(jiraOptions, bulkOptions, issues, func(chunk []*JiraBulkCreateIssueResult) error {
+		for _, r := range chunk {
+			results[r.Row] = r
+		}
+		return state.save(chunk)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	list := []*JiraBulkCreateIssueResult{}
+	for _, record := range records {
+		if r, ok := results[record.Row]; ok {
+			list = append(list, r)
+		}
+	}
+	return list, nil
+}
+
+func (j *Jira) BulkCreateIssuesFromInput(bulkOptions JiraBulkCreateIssueOptions, render func(record interface{}) ([]byte, error)) ([]*JiraBulkCreateIssueResult, error) {
+	return j.CustomBulkCreateIssuesFromInput(j.options, bulkOptions, render)
+}
@@ -0,0 +1,231 @@
+package vendors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/devopsext/tools/common"
+	"github.com/devopsext/utils"
+)
+
+// Jira Service Management customer requests, they keep request types and customer notifications unlike plain issues
+
+type JiraServiceDeskRequestOptions struct {
+	ServiceDesk     string // id, project key or project name
+	RequestType     string // id or name
+	Summary         string
+	Description     string
+	Fields          string // json map of additional request field values, content or path
+	Participants    []string
+	RaiseOnBehalfOf string
+}
+
+type JiraServiceDeskSLAOptions struct {
+	IdOrKey string
+}
+
+type JiraServiceDesk struct {
+	ID          string `json:"id"`
+	ProjectKey  string `json:"projectKey"`
+	ProjectName string `json:"projectName"`
+}
+
+type JiraServiceDeskRequestType struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type JiraServiceDeskRequest struct {
+	ServiceDeskID       string                 `json:"serviceDeskId"`
+	RequestTypeID       string                 `json:"requestTypeId"`
+	RequestFieldValues  map[string]interface{} `json:"requestFieldValues"`
+	RequestParticipants []string               `json:"requestParticipants,omitempty"`
+	RaiseOnBehalfOf     string                 `json:"raiseOnBehalfOf,omitempty"`
+}
+
+func jiraServiceDeskPath(format string, args ...interface{}) string {
+	return path.Join("rest/servicedeskapi", fmt.Sprintf(format, args...))
+}
+
+func (j *Jira) serviceDeskRequest(jiraOptions JiraOptions, method, p string, params url.Values, body []byte) ([]byte, error) {
+
+	u, err := url.Parse(jiraOptions.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, p)
+	if params != nil {
+		u.RawQuery = params.Encode()
+	}
+
+	headers := make(map[string]string)
+	headers["Content-Type"] = "application/json"
+	headers["Authorization"] = j.getAuth(jiraOptions)
+	headers["X-ExperimentalApi"] = "opt-in"
+
+	b, _, err := utils.HttpRequestRawWithHeadersOutCode(j.client, method, u.String(), headers, body)
+	if err != nil {
+		return nil, responseError(b, err)
+	}
+	return b, nil
+}
+
+// serviceDeskValues reads all pages of service desk api list
+func (j *Jira) serviceDeskValues(jiraOptions JiraOptions, p string, params url.Values) ([]json.RawMessage, error) {
+
+	if params == nil {
+		params = make(url.Values)
+	}
+
+	var values []json.RawMessage
+	for {
+		params.Set("start", strconv.Itoa(len(values)))
+
+		b, err := j.serviceDeskRequest(jiraOptions, http.MethodGet, p, params, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var r struct {
+			Values     []json.RawMessage `json:"values"`
+			IsLastPage bool              `json:"isLastPage"`
+		}
+		if err := json.Unmarshal(b, &r); err != nil {
+			return nil, err
+		}
+		values = append(values, r.Values...)
+		if r.IsLastPage || len(r.Values) == 0 {
+			return values, nil
+		}
+	}
+}
+
+func (j *Jira) findServiceDesk(jiraOptions JiraOptions, serviceDesk string) (string, error) {
+
+	if utils.IsEmpty(serviceDesk) {
+		return "", errors.New("jira service desk is empty")
+	}
+	if _, err := strconv.Atoi(serviceDesk); err == nil {
+		return serviceDesk, nil
+	}
+
+	values, err := j.serviceDeskValues(jiraOptions, jiraServiceDeskPath("servicedesk"), nil)
+	if err != nil {
+		return "", err
+	}
+	for _, v := range values {
+		var sd JiraServiceDesk
+		if err := json.Unmarshal(v, &sd); err != nil {
+			return "", err
+		}
+		if strings.EqualFold(sd.ProjectKey, serviceDesk) || strings.EqualFold(sd.ProjectName, serviceDesk) {
+			return sd.ID, nil
+		}
+	}
+	return "", fmt.Errorf("jira service desk %s is not found", serviceDesk)
+}
+
+func (j *Jira) findServiceDeskRequestType(jiraOptions JiraOptions, serviceDeskID, requestType string) (string, error) {
+
+	if utils.IsEmpty(requestType) {
+		return "", errors.New("jira service desk request type is empty")
+	}
+	if _, err := strconv.Atoi(requestType); err == nil {
+		return requestType, nil
+	}
+
+	params := make(url.Values)
+	params.Add("searchQuery", requestType)
+
+	values, err := j.serviceDeskValues(jiraOptions, jiraServiceDeskPath("servicedesk/%s/requesttype", serviceDeskID), params)
+	if err != nil {
+		return "", err
+	}
+
+	var names []string
+	for _, v := range values {
+		var rt JiraServiceDeskRequestType
+		if err := json.Unmarshal(v, &rt); err != nil {
+			return "", err
+		}
+		if strings.EqualFold(rt.Name, requestType) {
+			return rt.ID, nil
+		}
+		names = append(names, rt.Name)
+	}
+	return "", fmt.Errorf("jira service desk request type %s is not found, similar: %s", requestType, strings.Join(names, ", "))
+}
+
+func (j *Jira) CustomCreateServiceDeskRequest(jiraOptions JiraOptions, requestOptions JiraServiceDeskRequestOptions) ([]byte, error) {
+
+	serviceDeskID, err := j.findServiceDesk(jiraOptions, requestOptions.ServiceDesk)
+	if err != nil {
+		return nil, err
+	}
+
+	requestTypeID, err := j.findServiceDeskRequestType(jiraOptions, serviceDeskID, requestOptions.RequestType)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]interface{})
+	if !utils.IsEmpty(requestOptions.Fields) {
+		fields, err = common.ReadAndMarshal(requestOptions.Fields)
+		if err != nil {
+			return nil, err
+		}
+		if fields == nil {
+			fields = make(map[string]interface{})
+		}
+	}
+	if !utils.IsEmpty(requestOptions.Summary) {
+		fields["summary"] = requestOptions.Summary
+	}
+	if !utils.IsEmpty(requestOptions.Description) {
+		fields["description"] = requestOptions.Description
+	}
+
+	request := &JiraServiceDeskRequest{
+		ServiceDeskID:       serviceDeskID,
+		RequestTypeID:       requestTypeID,
+		RequestFieldValues:  fields,
+		RequestParticipants: common.RemoveEmptyStrings(requestOptions.Participants),
+		RaiseOnBehalfOf:     requestOptions.RaiseOnBehalfOf,
+	}
+
+	req, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	return j.serviceDeskRequest(jiraOptions, http.MethodPost, jiraServiceDeskPath("request"), nil, req)
+}
+
+func (j *Jira) CreateServiceDeskRequest(requestOptions JiraServiceDeskRequestOptions) ([]byte, error) {
+	return j.CustomCreateServiceDeskRequest(j.options, requestOptions)
+}
+
+func (j *Jira) CustomGetServiceDeskRequestSLA(jiraOptions JiraOptions, slaOptions JiraServiceDeskSLAOptions) ([]byte, error) {
+
+	if utils.IsEmpty(slaOptions.IdOrKey) {
+		return nil, errors.New("jira service desk request id or key is empty")
+	}
+
+	values, err := j.serviceDeskValues(jiraOptions, jiraServiceDeskPath("request/%s/sla", url.PathEscape(slaOptions.IdOrKey)), nil)
+	if err != nil {
+		return nil, err
+	}
+	if values == nil {
+		values = []json.RawMessage{}
+	}
+	return json.Marshal(values)
+}
+
+func (j *Jira) GetServiceDeskRequestSLA(slaOptions JiraServiceDeskSLAOptions) ([]byte, error) {
+	return j.CustomGetServiceDeskRequestSLA(j.options, slaOptions)
+}
@@ -0,0 +1,304 @@
+package vendors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/devopsext/utils"
+)
+
+const (
+	mattermostPostsPath     = "/api/v4/posts"
+	mattermostFilesPath     = "/api/v4/files"
+	mattermostReactionsPath = "/api/v4/reactions"
+	mattermostUsersMePath   = "/api/v4/users/me"
+)
+
+type MattermostOptions struct {
+	Timeout    int
+	Insecure   bool
+	URL        string
+	Token      string
+	Channel    string
+	Title      string
+	Message    string
+	FileName   string
+	File       string // content or path to file
+	ImageURL   string
+	ParentID   string
+	QuoteColor string
+}
+
+type MattermostReactionOptions struct {
+	Name string
+}
+
+type MattermostAttachment struct {
+	Fallback string `json:"fallback,omitempty"`
+	Color    string `json:"color,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Text     string `json:"text,omitempty"`
+	ImageURL string `json:"image_url,omitempty"`
+}
+
+type MattermostPostProps struct {
+	Attachments []*MattermostAttachment `json:"attachments,omitempty"`
+}
+
+type MattermostPost struct {
+	ChannelID string               `json:"channel_id"`
+	Message   string               `json:"message"`
+	RootID    string               `json:"root_id,omitempty"`
+	FileIDs   []string             `json:"file_ids,omitempty"`
+	Props     *MattermostPostProps `json:"props,omitempty"`
+}
+
+type MattermostReaction struct {
+	UserID    string `json:"user_id"`
+	PostID    string `json:"post_id"`
+	EmojiName string `json:"emoji_name"`
+}
+
+type MattermostFileInfo struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type MattermostFileUploadResponse struct {
+	FileInfos []*MattermostFileInfo `json:"file_infos"`
+}
+
+type MattermostUser struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
+
+type Mattermost struct {
+	client  *http.Client
+	options MattermostOptions
+}
+
+func (m *Mattermost) getAuth(opts MattermostOptions) string {
+
+	auth := ""
+	if !utils.IsEmpty(opts.Token) {
+		auth = fmt.Sprintf("Bearer %s", opts.Token)
+	}
+	return auth
+}
+
+func (m *Mattermost) apiURL(opts MattermostOptions, p string) (string, error) {
+
+	u, err := url.Parse(opts.URL)
+	if err != nil {
+		return "", err
+	}
+	u.Path = path.Join(u.Path, p)
+	return u.String(), nil
+}
+
+func (m *Mattermost) getAttachments(opts MattermostOptions) []*MattermostAttachment {
+
+	if utils.IsEmpty(opts.QuoteColor) && utils.IsEmpty(opts.ImageURL) {
+		return nil
+	}
+
+	a := &MattermostAttachment{
+		Color:    opts.QuoteColor,
+		ImageURL: opts.ImageURL,
+	}
+	if !utils.IsEmpty(opts.QuoteColor) {
+		a.Fallback = opts.Title
+		a.Text = opts.Message
+	}
+	return []*MattermostAttachment{a}
+}
+
+func (m *Mattermost) getMessage(opts MattermostOptions) string {
+
+	// message goes to attachment if quote color is set
+	if !utils.IsEmpty(opts.QuoteColor) {
+		if !utils.IsEmpty(opts.Title) {
+			return fmt.Sprintf("**%s**", opts.Title)
+		}
+		return ""
+	}
+	if !utils.IsEmpty(opts.Title) {
+		return fmt.Sprintf("**%s**\n%s", opts.Title, opts.Message)
+	}
+	return opts.Message
+}
+
+func (m *Mattermost) createPost(opts MattermostOptions, post *MattermostPost) ([]byte, error) {
+
+	u, err := m.apiURL(opts, mattermostPostsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := json.Marshal(post)
+	if err != nil {
+		return nil, err
+	}
+	return utils.HttpPostRaw(m.client, u, "application/json", m.getAuth(opts), req)
+}
+
+func (m *Mattermost) uploadFile(opts MattermostOptions) (*MattermostFileUploadResponse, error) {
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	defer func() {
+		w.Close()
+	}()
+
+	if err := w.WriteField("channel_id", opts.Channel); err != nil {
+		return nil, err
+	}
+
+	fw, err := w.CreateFormFile("files", opts.FileName)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := fw.Write([]byte(opts.File)); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	u, err := m.apiURL(opts, mattermostFilesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := utils.HttpPostRaw(m.client, u, w.FormDataContentType(), m.getAuth(opts), body.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	var r MattermostFileUploadResponse
+	err = json.Unmarshal(b, &r)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (m *Mattermost) getMe(opts MattermostOptions) (*MattermostUser, error) {
+
+	u, err := m.apiURL(opts, mattermostUsersMePath)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := utils.HttpGetRaw(m.client, u, "application/json", m.getAuth(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	var r MattermostUser
+	err = json.Unmarshal(b, &r)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (m *Mattermost) CustomSendMessage(mattermostOptions MattermostOptions) ([]byte, error) {
+
+	if utils.IsEmpty(mattermostOptions.Message) {
+		return nil, errors.New("mattermost message is empty")
+	}
+
+	post := &MattermostPost{
+		ChannelID: mattermostOptions.Channel,
+		Message:   m.getMessage(mattermostOptions),
+		RootID:    mattermostOptions.ParentID,
+	}
+
+	attachments := m.getAttachments(mattermostOptions)
+	if len(attachments) > 0 {
+		post.Props = &MattermostPostProps{Attachments: attachments}
+	}
+	return m.createPost(mattermostOptions, post)
+}
+
+func (m *Mattermost) SendMessage() ([]byte, error) {
+	return m.CustomSendMessage(m.options)
+}
+
+func (m *Mattermost) CustomSendFile(mattermostOptions MattermostOptions) ([]byte, error) {
+
+	if utils.IsEmpty(mattermostOptions.FileName) {
+		return nil, errors.New("mattermost file name is empty")
+	}
+
+	r, err := m.uploadFile(mattermostOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	post := &MattermostPost{
+		ChannelID: mattermostOptions.Channel,
+		Message:   m.getMessage(mattermostOptions),
+		RootID:    mattermostOptions.ParentID,
+	}
+	for _, fi := range r.FileInfos {
+		post.FileIDs = append(post.FileIDs, fi.ID)
+	}
+	return m.createPost(mattermostOptions, post)
+}
+
+func (m *Mattermost) SendFile() ([]byte, error) {
+	return m.CustomSendFile(m.options)
+}
+
+func (m *Mattermost) CustomAddReaction(mattermostOptions MattermostOptions, reactionOptions MattermostReactionOptions) ([]byte, error) {
+
+	if utils.IsEmpty(mattermostOptions.ParentID) {
+		return nil, errors.New("mattermost post ID is empty")
+	}
+
+	me, err := m.getMe(mattermostOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	reaction := &MattermostReaction{
+		UserID:    me.ID,
+		PostID:    mattermostOptions.ParentID,
+		EmojiName: reactionOptions.Name,
+	}
+
+	req, err := json.Marshal(reaction)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := m.apiURL(mattermostOptions, mattermostReactionsPath)
+	if err != nil {
+		return nil, err
+	}
+	return utils.HttpPostRaw(m.client, u, "application/json", m.getAuth(mattermostOptions), req)
+}
+
+func (m *Mattermost) AddReaction(options MattermostReactionOptions) ([]byte, error) {
+	return m.CustomAddReaction(m.options, options)
+}
+
+func NewMattermost(options MattermostOptions) *Mattermost {
+
+	mattermost := &Mattermost{
+		client:  utils.NewHttpClient(options.Timeout, options.Insecure),
+		options: options,
+	}
+	return mattermost
+}
@@ -0,0 +1,293 @@
+package vendors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/devopsext/tools/common"
+	"github.com/devopsext/utils"
+)
+
+type OpsgenieAlertOptions struct {
+	Title      string
+	Body       string
+	Priority   string
+	Responders []string // team:name, user:username, escalation:name, schedule:name
+	Alias      string
+	Tags       []string
+	Source     string
+	Entity     string
+}
+
+type OpsgenieAlertActionOptions struct {
+	ID     string // alert ID, alias is used if empty
+	User   string
+	Source string
+	Note   string
+}
+
+type OpsgenieGetAlertsOptions struct {
+	Query string
+	Limit int
+}
+
+type OpsgenieOnCallOptions struct {
+	Schedule string
+	Date     string
+	Flat     bool
+}
+
+type OpsgenieResponder struct {
+	Type     string `json:"type"`
+	ID       string `json:"id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+type OpsgenieAlert struct {
+	Message     string               `json:"message"`
+	Alias       string               `json:"alias,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Responders  []*OpsgenieResponder `json:"responders,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Entity      string               `json:"entity,omitempty"`
+	Source      string               `json:"source,omitempty"`
+	Priority    string               `json:"priority,omitempty"`
+}
+
+type OpsgenieAlertAction struct {
+	User   string `json:"user,omitempty"`
+	Source string `json:"source,omitempty"`
+	Note   string `json:"note,omitempty"`
+}
+
+type OpsgenieOptions struct {
+	Timeout  int
+	Insecure bool
+	URL      string
+	Token    string
+}
+
+type Opsgenie struct {
+	client  *http.Client
+	options OpsgenieOptions
+	logger  common.Logger
+}
+
+const (
+	opsgenieContentType   = "application/json"
+	opsgenieAlertsPath    = "/v2/alerts"
+	opsgenieSchedulesPath = "/v2/schedules"
+)
+
+func (o *Opsgenie) getAuth(options OpsgenieOptions) string {
+	auth := ""
+	if !utils.IsEmpty(options.Token) {
+		auth = fmt.Sprintf("GenieKey %s", options.Token)
+	}
+	return auth
+}
+
+func (o *Opsgenie) getResponders(responders []string) ([]*OpsgenieResponder, error) {
+
+	var r []*OpsgenieResponder
+	for _, s := range responders {
+
+		if utils.IsEmpty(s) {
+			continue
+		}
+		kv := strings.SplitN(strings.TrimSpace(s), ":", 2)
+		if len(kv) < 2 {
+			return nil, fmt.Errorf("opsgenie responder %s should be type:name", s)
+		}
+
+		responder := &OpsgenieResponder{Type: kv[0]}
+		switch kv[0] {
+		case "user":
+			responder.Username = kv[1]
+		case "team", "escalation", "schedule":
+			responder.Name = kv[1]
+		default:
+			return nil, fmt.Errorf("opsgenie responder type %s is not supported", kv[0])
+		}
+		r = append(r, responder)
+	}
+	return r, nil
+}
+
+func (o *Opsgenie) alertIdentifier(alertOptions OpsgenieAlertOptions, actionOptions OpsgenieAlertActionOptions) (string, string, error) {
+
+	if !utils.IsEmpty(actionOptions.ID) {
+		return actionOptions.ID, "id", nil
+	}
+	if !utils.IsEmpty(alertOptions.Alias) {
+		return alertOptions.Alias, "alias", nil
+	}
+	return "", "", errors.New("opsgenie alert ID or alias is required")
+}
+
+// identifierPath escapes identifier, aliases and schedule names may contain slashes and spaces
+func (o *Opsgenie) identifierPath(u *url.URL, prefix, identifier, suffix string) {
+
+	escaped := u.EscapedPath()
+	u.Path = fmt.Sprintf("%s/%s/%s", path.Join(u.Path, prefix), identifier, suffix)
+	u.RawPath = fmt.Sprintf("%s/%s/%s", path.Join(escaped, prefix), url.PathEscape(identifier), suffix)
+}
+
+func (o *Opsgenie) CustomCreateAlert(options OpsgenieOptions, alertOptions OpsgenieAlertOptions) ([]byte, error) {
+
+	u, err := url.Parse(options.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, opsgenieAlertsPath)
+
+	responders, err := o.getResponders(alertOptions.Responders)
+	if err != nil {
+		return nil, err
+	}
+
+	alert := &OpsgenieAlert{
+		Message:     alertOptions.Title,
+		Alias:       alertOptions.Alias,
+		Description: alertOptions.Body,
+		Responders:  responders,
+		Tags:        common.RemoveEmptyStrings(alertOptions.Tags),
+		Entity:      alertOptions.Entity,
+		Source:      alertOptions.Source,
+		Priority:    alertOptions.Priority,
+	}
+
+	data, err := json.Marshal(alert)
+	if err != nil {
+		return nil, err
+	}
+	return utils.HttpPostRaw(o.client, u.String(), opsgenieContentType, o.getAuth(options), data)
+}
+
+func (o *Opsgenie) CreateAlert(alertOptions OpsgenieAlertOptions) ([]byte, error) {
+	return o.CustomCreateAlert(o.options, alertOptions)
+}
+
+func (o *Opsgenie) alertAction(options OpsgenieOptions, alertOptions OpsgenieAlertOptions, actionOptions OpsgenieAlertActionOptions, action string) ([]byte, error) {
+
+	identifier, identifierType, err := o.alertIdentifier(alertOptions, actionOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(options.URL)
+	if err != nil {
+		return nil, err
+	}
+	o.identifierPath(u, opsgenieAlertsPath, identifier, action)
+
+	var params = make(url.Values)
+	params.Add("identifierType", identifierType)
+	u.RawQuery = params.Encode()
+
+	data, err := json.Marshal(&OpsgenieAlertAction{
+		User:   actionOptions.User,
+		Source: actionOptions.Source,
+		Note:   actionOptions.Note,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return utils.HttpPostRaw(o.client, u.String(), opsgenieContentType, o.getAuth(options), data)
+}
+
+func (o *Opsgenie) CustomCloseAlert(options OpsgenieOptions, alertOptions OpsgenieAlertOptions, actionOptions OpsgenieAlertActionOptions) ([]byte, error) {
+	return o.alertAction(options, alertOptions, actionOptions, "close")
+}
+
+func (o *Opsgenie) CloseAlert(alertOptions OpsgenieAlertOptions, actionOptions OpsgenieAlertActionOptions) ([]byte, error) {
+	return o.CustomCloseAlert(o.options, alertOptions, actionOptions)
+}
+
+func (o *Opsgenie) CustomAckAlert(options OpsgenieOptions, alertOptions OpsgenieAlertOptions, actionOptions OpsgenieAlertActionOptions) ([]byte, error) {
+	return o.alertAction(options, alertOptions, actionOptions, "acknowledge")
+}
+
+func (o *Opsgenie) AckAlert(alertOptions OpsgenieAlertOptions, actionOptions OpsgenieAlertActionOptions) ([]byte, error) {
+	return o.CustomAckAlert(o.options, alertOptions, actionOptions)
+}
+
+func (o *Opsgenie) CustomAddAlertNote(options OpsgenieOptions, alertOptions OpsgenieAlertOptions, actionOptions OpsgenieAlertActionOptions) ([]byte, error) {
+
+	if utils.IsEmpty(actionOptions.Note) {
+		return nil, errors.New("opsgenie note is empty")
+	}
+	return o.alertAction(options, alertOptions, actionOptions, "notes")
+}
+
+func (o *Opsgenie) AddAlertNote(alertOptions OpsgenieAlertOptions, actionOptions OpsgenieAlertActionOptions) ([]byte, error) {
+	return o.CustomAddAlertNote(o.options, alertOptions, actionOptions)
+}
+
+func (o *Opsgenie) CustomGetAlerts(options OpsgenieOptions, getOptions OpsgenieGetAlertsOptions) ([]byte, error) {
+
+	u, err := url.Parse(options.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, opsgenieAlertsPath)
+
+	var params = make(url.Values)
+	if !utils.IsEmpty(getOptions.Query) {
+		params.Add("query", getOptions.Query)
+	}
+	if getOptions.Limit > 0 {
+		params.Add("limit", fmt.Sprintf("%d", getOptions.Limit))
+	}
+	params.Add("sort", "createdAt")
+	params.Add("order", "desc")
+	u.RawQuery = params.Encode()
+
+	return utils.HttpGetRaw(o.client, u.String(), opsgenieContentType, o.getAuth(options))
+}
+
+func (o *Opsgenie) GetAlerts(getOptions OpsgenieGetAlertsOptions) ([]byte, error) {
+	return o.CustomGetAlerts(o.options, getOptions)
+}
+
+func (o *Opsgenie) CustomGetOnCalls(options OpsgenieOptions, onCallOptions OpsgenieOnCallOptions) ([]byte, error) {
+
+	if utils.IsEmpty(onCallOptions.Schedule) {
+		return nil, errors.New("opsgenie schedule is empty")
+	}
+
+	u, err := url.Parse(options.URL)
+	if err != nil {
+		return nil, err
+	}
+	o.identifierPath(u, opsgenieSchedulesPath, onCallOptions.Schedule, "on-calls")
+
+	var params = make(url.Values)
+	params.Add("scheduleIdentifierType", "name")
+	params.Add("flat", fmt.Sprintf("%t", onCallOptions.Flat))
+	if !utils.IsEmpty(onCallOptions.Date) {
+		params.Add("date", onCallOptions.Date)
+	}
+	u.RawQuery = params.Encode()
+
+	return utils.HttpGetRaw(o.client, u.String(), opsgenieContentType, o.getAuth(options))
+}
+
+func (o *Opsgenie) GetOnCalls(onCallOptions OpsgenieOnCallOptions) ([]byte, error) {
+	return o.CustomGetOnCalls(o.options, onCallOptions)
+}
+
+func NewOpsgenie(options OpsgenieOptions, logger common.Logger) *Opsgenie {
+
+	return &Opsgenie{
+		client:  utils.NewHttpClient(options.Timeout, options.Insecure),
+		options: options,
+		logger:  logger,
+	}
+}
@@ -0,0 +1,326 @@
+package vendors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/devopsext/utils"
+)
+
+const (
+	rocketChatPostMessagePath = "/api/v1/chat.postMessage"
+	rocketChatReactPath       = "/api/v1/chat.react"
+	rocketChatRoomsUploadPath = "/api/v1/rooms.upload/%s"
+	rocketChatRoomsInfoPath   = "/api/v1/rooms.info"
+	rocketChatIMCreatePath    = "/api/v1/im.create"
+)
+
+type RocketChatOptions struct {
+	Timeout    int
+	Insecure   bool
+	URL        string
+	UserID     string
+	Token      string
+	Channel    string // #channel, @user or room ID
+	Title      string
+	Message    string
+	FileName   string
+	File       string // content or path to file
+	ImageURL   string
+	ParentID   string
+	QuoteColor string
+}
+
+type RocketChatReactionOptions struct {
+	Name string
+}
+
+type RocketChatAttachment struct {
+	Color    string `json:"color,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Text     string `json:"text,omitempty"`
+	ImageURL string `json:"image_url,omitempty"`
+}
+
+type RocketChatMessage struct {
+	Channel     string                  `json:"channel,omitempty"`
+	RoomID      string                  `json:"roomId,omitempty"`
+	Text        string                  `json:"text"`
+	TMID        string                  `json:"tmid,omitempty"`
+	Attachments []*RocketChatAttachment `json:"attachments,omitempty"`
+}
+
+type RocketChatReaction struct {
+	MessageID   string `json:"messageId"`
+	Emoji       string `json:"emoji"`
+	ShouldReact bool   `json:"shouldReact"`
+}
+
+type RocketChatRoom struct {
+	ID   string `json:"_id"`
+	Name string `json:"name"`
+}
+
+type RocketChatRoomInfoResponse struct {
+	Room    *RocketChatRoom `json:"room"`
+	Success bool            `json:"success"`
+}
+
+type RocketChat struct {
+	client  *http.Client
+	options RocketChatOptions
+}
+
+func (r *RocketChat) getHeaders(opts RocketChatOptions, contentType string) map[string]string {
+
+	headers := make(map[string]string)
+	headers["Content-Type"] = contentType
+	headers["X-User-Id"] = opts.UserID
+	headers["X-Auth-Token"] = opts.Token
+	return headers
+}
+
+func (r *RocketChat) apiURL(opts RocketChatOptions, p string) (*url.URL, error) {
+
+	u, err := url.Parse(opts.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, p)
+	return u, nil
+}
+
+func (r *RocketChat) isRoomName(channel string) bool {
+	return strings.HasPrefix(channel, "#") || strings.HasPrefix(channel, "@")
+}
+
+// getDirectRoomID opens direct message room with @user, existing room is returned if any
+func (r *RocketChat) getDirectRoomID(opts RocketChatOptions) (string, error) {
+
+	u, err := r.apiURL(opts, rocketChatIMCreatePath)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := json.Marshal(map[string]string{"username": strings.TrimPrefix(opts.Channel, "@")})
+	if err != nil {
+		return "", err
+	}
+
+	b, err := utils.HttpPostRawWithHeaders(r.client, u.String(), r.getHeaders(opts, "application/json"), req)
+	if err != nil {
+		return "", err
+	}
+
+	var info RocketChatRoomInfoResponse
+	err = json.Unmarshal(b, &info)
+	if err != nil {
+		return "", err
+	}
+	if info.Room == nil || utils.IsEmpty(info.Room.ID) {
+		return "", fmt.Errorf("rocketchat direct room with %s not found", opts.Channel)
+	}
+	return info.Room.ID, nil
+}
+
+// rooms.upload works with room IDs only, so #channel and @user have to be resolved
+func (r *RocketChat) getRoomID(opts RocketChatOptions) (string, error) {
+
+	if strings.HasPrefix(opts.Channel, "@") {
+		return r.getDirectRoomID(opts)
+	}
+	if !strings.HasPrefix(opts.Channel, "#") {
+		return opts.Channel, nil
+	}
+
+	u, err := r.apiURL(opts, rocketChatRoomsInfoPath)
+	if err != nil {
+		return "", err
+	}
+
+	params := make(url.Values)
+	params.Add("roomName", strings.TrimPrefix(opts.Channel, "#"))
+	u.RawQuery = params.Encode()
+
+	b, err := utils.HttpGetRawWithHeaders(r.client, u.String(), r.getHeaders(opts, "application/json"))
+	if err != nil {
+		return "", err
+	}
+
+	var info RocketChatRoomInfoResponse
+	err = json.Unmarshal(b, &info)
+	if err != nil {
+		return "", err
+	}
+	if info.Room == nil || utils.IsEmpty(info.Room.ID) {
+		return "", fmt.Errorf("rocketchat room %s not found", opts.Channel)
+	}
+	return info.Room.ID, nil
+}
+
+func (r *RocketChat) getText(opts RocketChatOptions) string {
+
+	// message goes to attachment if quote color is set
+	if !utils.IsEmpty(opts.QuoteColor) {
+		if !utils.IsEmpty(opts.Title) {
+			return fmt.Sprintf("*%s*", opts.Title)
+		}
+		return ""
+	}
+	if !utils.IsEmpty(opts.Title) {
+		return fmt.Sprintf("*%s*\n%s", opts.Title, opts.Message)
+	}
+	return opts.Message
+}
+
+func (r *RocketChat) CustomSendMessage(rocketChatOptions RocketChatOptions) ([]byte, error) {
+
+	if utils.IsEmpty(rocketChatOptions.Message) {
+		return nil, errors.New("rocketchat message is empty")
+	}
+
+	m := &RocketChatMessage{
+		Text: r.getText(rocketChatOptions),
+		TMID: rocketChatOptions.ParentID,
+	}
+
+	if r.isRoomName(rocketChatOptions.Channel) {
+		m.Channel = rocketChatOptions.Channel
+	} else {
+		m.RoomID = rocketChatOptions.Channel
+	}
+
+	if !utils.IsEmpty(rocketChatOptions.QuoteColor) || !utils.IsEmpty(rocketChatOptions.ImageURL) {
+		a := &RocketChatAttachment{
+			Color:    rocketChatOptions.QuoteColor,
+			ImageURL: rocketChatOptions.ImageURL,
+		}
+		if !utils.IsEmpty(rocketChatOptions.QuoteColor) {
+			a.Text = rocketChatOptions.Message
+		}
+		m.Attachments = []*RocketChatAttachment{a}
+	}
+
+	req, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := r.apiURL(rocketChatOptions, rocketChatPostMessagePath)
+	if err != nil {
+		return nil, err
+	}
+	return utils.HttpPostRawWithHeaders(r.client, u.String(), r.getHeaders(rocketChatOptions, "application/json"), req)
+}
+
+func (r *RocketChat) SendMessage() ([]byte, error) {
+	return r.CustomSendMessage(r.options)
+}
+
+func (r *RocketChat) CustomSendFile(rocketChatOptions RocketChatOptions) ([]byte, error) {
+
+	if utils.IsEmpty(rocketChatOptions.FileName) {
+		return nil, errors.New("rocketchat file name is empty")
+	}
+
+	roomID, err := r.getRoomID(rocketChatOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	defer func() {
+		w.Close()
+	}()
+
+	if !utils.IsEmpty(rocketChatOptions.Message) {
+		if err := w.WriteField("msg", rocketChatOptions.Message); err != nil {
+			return nil, err
+		}
+	}
+
+	if !utils.IsEmpty(rocketChatOptions.Title) {
+		if err := w.WriteField("description", rocketChatOptions.Title); err != nil {
+			return nil, err
+		}
+	}
+
+	if !utils.IsEmpty(rocketChatOptions.ParentID) {
+		if err := w.WriteField("tmid", rocketChatOptions.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	fw, err := w.CreateFormFile("file", rocketChatOptions.FileName)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := fw.Write([]byte(rocketChatOptions.File)); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	u, err := r.apiURL(rocketChatOptions, fmt.Sprintf(rocketChatRoomsUploadPath, roomID))
+	if err != nil {
+		return nil, err
+	}
+	return utils.HttpPostRawWithHeaders(r.client, u.String(), r.getHeaders(rocketChatOptions, w.FormDataContentType()), body.Bytes())
+}
+
+func (r *RocketChat) SendFile() ([]byte, error) {
+	return r.CustomSendFile(r.options)
+}
+
+func (r *RocketChat) CustomAddReaction(rocketChatOptions RocketChatOptions, reactionOptions RocketChatReactionOptions) ([]byte, error) {
+
+	if utils.IsEmpty(rocketChatOptions.ParentID) {
+		return nil, errors.New("rocketchat message ID is empty")
+	}
+
+	emoji := reactionOptions.Name
+	if !strings.HasPrefix(emoji, ":") {
+		emoji = fmt.Sprintf(":%s:", emoji)
+	}
+
+	reaction := &RocketChatReaction{
+		MessageID:   rocketChatOptions.ParentID,
+		Emoji:       emoji,
+		ShouldReact: true,
+	}
+
+	req, err := json.Marshal(reaction)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := r.apiURL(rocketChatOptions, rocketChatReactPath)
+	if err != nil {
+		return nil, err
+	}
+	return utils.HttpPostRawWithHeaders(r.client, u.String(), r.getHeaders(rocketChatOptions, "application/json"), req)
+}
+
+func (r *RocketChat) AddReaction(options RocketChatReactionOptions) ([]byte, error) {
+	return r.CustomAddReaction(r.options, options)
+}
+
+func NewRocketChat(options RocketChatOptions) *RocketChat {
+
+	rocketChat := &RocketChat{
+		client:  utils.NewHttpClient(options.Timeout, options.Insecure),
+		options: options,
+	}
+	return rocketChat
+}
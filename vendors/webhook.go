@@ -0,0 +1,237 @@
+package vendors
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devopsext/tools/common"
+	"github.com/devopsext/utils"
+)
+
+type WebhookOptions struct {
+	Timeout     int
+	Insecure    bool
+	URL         string
+	Method      string
+	Headers     []string // Name: value
+	ContentType string
+	Body        string
+	Retries     int
+	RetryDelay  int
+}
+
+type WebhookSignatureOptions struct {
+	Secret string
+	Style  string // github, stripe
+	Header string
+}
+
+type WebhookValidateOptions struct {
+	Codes  []int
+	Assert string // jsonata expression evaluated against response body, must be true
+}
+
+type WebhookResponse struct {
+	Code int         `json:"code"`
+	Body interface{} `json:"body,omitempty"`
+}
+
+type Webhook struct {
+	client  *http.Client
+	options WebhookOptions
+	logger  common.Logger
+}
+
+const (
+	WebhookSignatureGitHub = "github"
+	WebhookSignatureStripe = "stripe"
+)
+
+func (w *Webhook) getHeaders(options WebhookOptions, data []byte) (map[string]string, error) {
+
+	headers := make(map[string]string)
+	if !utils.IsEmpty(options.ContentType) && len(data) > 0 {
+		headers["Content-Type"] = options.ContentType
+	}
+
+	for _, h := range options.Headers {
+
+		if utils.IsEmpty(h) {
+			continue
+		}
+		sep := ":"
+		if !strings.Contains(h, sep) {
+			sep = "="
+		}
+		kv := strings.SplitN(h, sep, 2)
+		if len(kv) < 2 || utils.IsEmpty(strings.TrimSpace(kv[0])) {
+			return nil, fmt.Errorf("webhook header %s should be name: value", h)
+		}
+		headers[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return headers, nil
+}
+
+func (w *Webhook) hmacSHA256(secret string, data []byte) string {
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// github: X-Hub-Signature-256: sha256=<hex>
+// stripe: Stripe-Signature: t=<timestamp>,v1=<hex of "<timestamp>.<body>">
+func (w *Webhook) sign(signatureOptions WebhookSignatureOptions, body []byte, headers map[string]string) error {
+
+	if utils.IsEmpty(signatureOptions.Secret) {
+		return nil
+	}
+
+	header := signatureOptions.Header
+	value := ""
+
+	switch strings.ToLower(signatureOptions.Style) {
+	case WebhookSignatureGitHub, "":
+		if utils.IsEmpty(header) {
+			header = "X-Hub-Signature-256"
+		}
+		value = fmt.Sprintf("sha256=%s", w.hmacSHA256(signatureOptions.Secret, body))
+	case WebhookSignatureStripe:
+		if utils.IsEmpty(header) {
+			header = "Stripe-Signature"
+		}
+		t := strconv.FormatInt(time.Now().Unix(), 10)
+		payload := append([]byte(t+"."), body...)
+		value = fmt.Sprintf("t=%s,v1=%s", t, w.hmacSHA256(signatureOptions.Secret, payload))
+	default:
+		return fmt.Errorf("webhook signature style %s is not supported", signatureOptions.Style)
+	}
+
+	headers[header] = value
+	return nil
+}
+
+func (w *Webhook) expected(validateOptions WebhookValidateOptions, code int) bool {
+
+	for _, c := range validateOptions.Codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// retryable skips codes which are expected, they are answers rather than failures
+func (w *Webhook) retryable(validateOptions WebhookValidateOptions, code int, err error) bool {
+
+	if err == nil || w.expected(validateOptions, code) {
+		return false
+	}
+	return code == 0 || code == http.StatusTooManyRequests || code >= 500
+}
+
+func (w *Webhook) validate(validateOptions WebhookValidateOptions, code int, body []byte, err error) error {
+
+	if len(validateOptions.Codes) > 0 {
+		if !w.expected(validateOptions, code) {
+			return fmt.Errorf("webhook response code %d is not expected %v", code, validateOptions.Codes)
+		}
+	} else if err != nil {
+		return err
+	}
+
+	if utils.IsEmpty(validateOptions.Assert) {
+		return nil
+	}
+
+	var obj interface{}
+	if err := json.Unmarshal(body, &obj); err != nil {
+		return fmt.Errorf("webhook response is not json: %s", err)
+	}
+
+	v, err := common.NewJsonata(common.JsonataOptions{}).Eval(obj, validateOptions.Assert)
+	if err != nil {
+		return err
+	}
+	if b, ok := v.(bool); !ok || !b {
+		return fmt.Errorf("webhook assertion %s failed", validateOptions.Assert)
+	}
+	return nil
+}
+
+func (w *Webhook) CustomSend(options WebhookOptions, signatureOptions WebhookSignatureOptions, validateOptions WebhookValidateOptions) ([]byte, error) {
+
+	if utils.IsEmpty(options.URL) {
+		return nil, errors.New("webhook url is empty")
+	}
+
+	method := strings.ToUpper(options.Method)
+	if utils.IsEmpty(method) {
+		method = http.MethodPost
+	}
+
+	var data []byte
+	if !utils.IsEmpty(options.Body) {
+		data = []byte(options.Body)
+	}
+
+	headers, err := w.getHeaders(options, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var body []byte
+	var code int
+	for attempt := 0; ; attempt++ {
+
+		// sign on every attempt, stripe style signature contains timestamp
+		if err := w.sign(signatureOptions, data, headers); err != nil {
+			return nil, err
+		}
+
+		body, code, err = utils.HttpRequestRawWithHeadersOutCode(w.client, method, options.URL, headers, data)
+		if !w.retryable(validateOptions, code, err) || attempt >= options.Retries {
+			break
+		}
+		if w.logger != nil {
+			w.logger.Debug("Webhook %s %s attempt %d failed: %s", method, options.URL, attempt+1, err)
+		}
+		time.Sleep(time.Duration(options.RetryDelay) * time.Second)
+	}
+
+	if err := w.validate(validateOptions, code, body, err); err != nil {
+		return body, err
+	}
+
+	response := &WebhookResponse{Code: code}
+	if len(body) > 0 {
+		var obj interface{}
+		if json.Unmarshal(body, &obj) == nil {
+			response.Body = obj
+		} else {
+			response.Body = string(body)
+		}
+	}
+	return common.JsonMarshal(response)
+}
+
+func (w *Webhook) Send(signatureOptions WebhookSignatureOptions, validateOptions WebhookValidateOptions) ([]byte, error) {
+	return w.CustomSend(w.options, signatureOptions, validateOptions)
+}
+
+func NewWebhook(options WebhookOptions, logger common.Logger) *Webhook {
+
+	return &Webhook{
+		client:  utils.NewHttpClient(options.Timeout, options.Insecure),
+		options: options,
+		logger:  logger,
+	}
+}